@@ -30,24 +30,10 @@ func main() {
 	gEngine.Start(game)
 }
 
-type keyState struct {
-	prevState uint8
-}
-
-func (ks *keyState) isKeyUp(state uint8) bool {
-	up := false
-	if ks.prevState == 0 && state == 1 {
-		up = true
-	}
-
-	ks.prevState = state
-	return up
-}
-
 type particlesGame struct {
 	particles *engine.ParticleSystem
 
-	tKey *keyState
+	actions *engine.InputMap
 }
 
 func newGame() *particlesGame {
@@ -55,13 +41,15 @@ func newGame() *particlesGame {
 
 	g.particles = engine.NewParticleSystem(200, g.particleRender, g.particleTrigger)
 
-	g.tKey = new(keyState)
+	g.actions = engine.NewInputMap(gEngine.Input())
+	g.actions.AddAction("trigger", engine.ActionDigital)
+	g.actions.Bind("trigger", engine.NewKeyBinding(sdl.SCANCODE_T, engine.ModNone))
+
 	return g
 }
 
-func (pg *particlesGame) Update(dt float64, keyState []uint8) {
-	// fmt.Printf("keys: %v\n", keyState)
-	if pg.tKey.isKeyUp(keyState[sdl.SCANCODE_T]) {
+func (pg *particlesGame) Update(dt float64, input *engine.Input) {
+	if pg.actions.IsPressed("trigger") {
 		pg.particles.TriggerParticle()
 	}
 
@@ -70,7 +58,7 @@ func (pg *particlesGame) Update(dt float64, keyState []uint8) {
 	pg.particles.Update(dt)
 }
 
-func (pg *particlesGame) Render(pixels *image.RGBA) {
+func (pg *particlesGame) Render(pixels *image.RGBA, alpha float64) {
 	pg.particles.Render(pixels)
 }
 
@@ -99,4 +87,6 @@ func (pg *particlesGame) particleTrigger(particle *engine.Particle, system *engi
 
 	particle.Position.Set2Components(float64(gEngine.Width/2), float64(gEngine.Height/2))
 	particle.Duration = 2.0
+	// Brighten quickly then settle
+	particle.Easing = engine.EaseOutQuad
 }
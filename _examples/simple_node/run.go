@@ -33,20 +33,15 @@ func main() {
 }
 
 type aGame struct {
-	tKey *keyState
-
 	wgroup engine.IGroupNode
 	white  engine.INode
 	ogroup engine.IGroupNode
 	orange engine.INode
 	blue   engine.INode
-
-	angle float64
 }
 
 func newGame() *aGame {
 	g := new(aGame)
-	g.tKey = new(keyState)
 
 	// g.build1()
 	g.build2()
@@ -75,7 +70,10 @@ func (pg *aGame) build2() {
 	pg.orange.SetPositionBy2Comp(50, 0)
 	pg.orange.SetScaleUniform(15)
 
-	pg.angle = 0.0
+	// The white rectangle slowly counter rotates while the orange orbits
+	actions := gEngine.Actions()
+	actions.Run(pg.white, engine.NewForever(engine.NewRotateBy(1.0, -30.0)), "spin")
+	actions.Run(pg.ogroup, engine.NewForever(engine.NewRotateBy(1.0, 120.0)), "spin")
 }
 
 func (pg *aGame) build1() {
@@ -96,37 +94,22 @@ func (pg *aGame) build1() {
 	pg.orange.SetPositionBy2Comp(150, 100)
 	pg.orange.SetScaleUniform(25)
 
-	pg.angle = 0.0
-
 	// root.Add(g.rect2)
 }
 
-func (pg *aGame) Update(dt float64, keyState []uint8) {
-	// fmt.Printf("keys: %v\n", keyState)
-	if pg.tKey.isKeyUp(keyState[sdl.SCANCODE_T]) {
+func (pg *aGame) Update(dt float64, input *engine.Input) {
+	// T toggles the spinning
+	if input.IsKeyPressed(sdl.SCANCODE_T) {
+		actions := gEngine.Actions()
+		for _, r := range actions.Running("spin") {
+			if r.IsPaused() {
+				r.Resume()
+			} else {
+				r.Pause()
+			}
+		}
 	}
-
-	pg.white.SetRotationByDegree(-pg.angle / 4)
-	pg.ogroup.SetRotationByDegree(pg.angle)
-	pg.angle += 2.0
-}
-
-func (pg *aGame) Render(pixels *image.RGBA) {
-}
-
-// -----------------------------------------------------------------------
-// Keys
-// -----------------------------------------------------------------------
-type keyState struct {
-	prevState uint8
 }
 
-func (ks *keyState) isKeyUp(state uint8) bool {
-	up := false
-	if ks.prevState == 0 && state == 1 {
-		up = true
-	}
-
-	ks.prevState = state
-	return up
+func (pg *aGame) Render(pixels *image.RGBA, alpha float64) {
 }
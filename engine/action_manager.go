@@ -0,0 +1,192 @@
+package engine
+
+// RunningAction is an action running on a node. It can be paused, sped
+// up or cancelled on its own or, through the ActionManager, by tag.
+type RunningAction struct {
+	action IAction
+	target INode
+	tag    string
+
+	timer   *Timer
+	elapsed float64
+	speed   float64
+	paused  bool
+}
+
+// Action returns the action being run.
+func (r *RunningAction) Action() IAction {
+	return r.action
+}
+
+// Target returns the node the action runs on.
+func (r *RunningAction) Target() INode {
+	return r.target
+}
+
+// Tag returns the tag the action was run with.
+func (r *RunningAction) Tag() string {
+	return r.tag
+}
+
+// Pause stops the action advancing until resumed.
+func (r *RunningAction) Pause() {
+	r.paused = true
+}
+
+// Resume continues a paused action.
+func (r *RunningAction) Resume() {
+	r.paused = false
+}
+
+// IsPaused is true while the action is paused.
+func (r *RunningAction) IsPaused() bool {
+	return r.paused
+}
+
+// SetSpeed scales the rate the action runs at, 1 is normal speed.
+func (r *RunningAction) SetSpeed(speed float64) {
+	r.speed = speed
+}
+
+// Speed returns the rate the action runs at.
+func (r *RunningAction) Speed() float64 {
+	return r.speed
+}
+
+// Cancel stops the action where it is.
+func (r *RunningAction) Cancel() {
+	r.timer.Cancel()
+}
+
+// IsDone is true once the action has completed or been cancelled,
+// including by its node leaving the running scene.
+func (r *RunningAction) IsDone() bool {
+	return !r.timer.IsActive()
+}
+
+func (r *RunningAction) advance(dt float64) {
+	if r.paused {
+		return
+	}
+
+	r.elapsed += dt * r.speed
+
+	duration := r.action.Duration()
+	if r.elapsed >= duration {
+		r.action.update(duration)
+		r.timer.Cancel()
+		return
+	}
+
+	r.action.update(r.elapsed)
+}
+
+// ActionManager runs actions on nodes and groups them by tag. Actions
+// advance with their node's timers so they stop while the clock is
+// paused, follow any time scale and are cancelled when the node leaves
+// the running scene.
+type ActionManager struct {
+	running []*RunningAction
+}
+
+// NewActionManager creates an empty manager.
+func NewActionManager() *ActionManager {
+	return new(ActionManager)
+}
+
+// Run starts action on target. tag may be empty.
+func (m *ActionManager) Run(target INode, action IAction, tag string) *RunningAction {
+	m.prune()
+
+	r := &RunningAction{action: action, target: target, tag: tag, speed: 1.0}
+	action.start(target)
+	r.timer = target.Schedule(r.advance)
+
+	m.running = append(m.running, r)
+
+	return r
+}
+
+// Running returns the actions with tag that haven't finished.
+func (m *ActionManager) Running(tag string) []*RunningAction {
+	m.prune()
+
+	var running []*RunningAction
+	for _, r := range m.running {
+		if r.tag == tag {
+			running = append(running, r)
+		}
+	}
+	return running
+}
+
+// RunningOn returns the actions running on target.
+func (m *ActionManager) RunningOn(target INode) []*RunningAction {
+	m.prune()
+
+	var running []*RunningAction
+	for _, r := range m.running {
+		if r.target == target {
+			running = append(running, r)
+		}
+	}
+	return running
+}
+
+// Count returns the number of actions that haven't finished.
+func (m *ActionManager) Count() int {
+	m.prune()
+	return len(m.running)
+}
+
+// Pause pauses every action with tag.
+func (m *ActionManager) Pause(tag string) {
+	for _, r := range m.Running(tag) {
+		r.Pause()
+	}
+}
+
+// Resume resumes every action with tag.
+func (m *ActionManager) Resume(tag string) {
+	for _, r := range m.Running(tag) {
+		r.Resume()
+	}
+}
+
+// SetSpeed sets the speed of every action with tag.
+func (m *ActionManager) SetSpeed(tag string, speed float64) {
+	for _, r := range m.Running(tag) {
+		r.SetSpeed(speed)
+	}
+}
+
+// Cancel cancels every action with tag.
+func (m *ActionManager) Cancel(tag string) {
+	for _, r := range m.Running(tag) {
+		r.Cancel()
+	}
+	m.prune()
+}
+
+// CancelOn cancels every action running on target.
+func (m *ActionManager) CancelOn(target INode) {
+	for _, r := range m.RunningOn(target) {
+		r.Cancel()
+	}
+	m.prune()
+}
+
+// prune drops actions that have finished, been cancelled or whose node
+// has left the scene.
+func (m *ActionManager) prune() {
+	running := m.running[:0]
+	for _, r := range m.running {
+		if !r.IsDone() {
+			running = append(running, r)
+		}
+	}
+	for i := len(running); i < len(m.running); i++ {
+		m.running[i] = nil
+	}
+	m.running = running
+}
@@ -0,0 +1,439 @@
+package engine
+
+import (
+	"image/color"
+	"math"
+)
+
+// IAction is a time-based change to a node, for example, a move or a
+// fade. Actions compose with Sequence, Spawn, Repeat, Forever and Ease,
+// and are run with an ActionManager.
+//
+// An action holds the state of its current run so an instance can only
+// run on one node at a time.
+type IAction interface {
+	// Duration in seconds. Instant actions are 0 and Forever is +Inf.
+	Duration() float64
+
+	// start begins a run on target, capturing any start values.
+	start(target INode)
+	// update applies the action elapsed seconds into the run. elapsed
+	// stays within the duration, except where an easing overshoots.
+	update(elapsed float64)
+}
+
+// interval is the base of actions that interpolate over a duration.
+type interval struct {
+	duration float64
+	target   INode
+}
+
+func (a *interval) Duration() float64 {
+	return a.duration
+}
+
+// progress returns elapsed as a fraction of the duration.
+func (a *interval) progress(elapsed float64) float64 {
+	if a.duration <= 0.0 {
+		return 1.0
+	}
+	return elapsed / a.duration
+}
+
+func lerp(from, to, p float64) float64 {
+	return from + (to-from)*p
+}
+
+// -----------------------------------------------------------------
+// Move
+// -----------------------------------------------------------------
+
+type moveAction struct {
+	interval
+	relative bool
+	x, y     float64
+
+	startX, startY float64
+	endX, endY     float64
+}
+
+// NewMoveTo moves a node to x,y.
+func NewMoveTo(duration, x, y float64) IAction {
+	return &moveAction{interval: interval{duration: duration}, x: x, y: y}
+}
+
+// NewMoveBy moves a node by dx,dy from wherever it is when started.
+func NewMoveBy(duration, dx, dy float64) IAction {
+	return &moveAction{interval: interval{duration: duration}, relative: true, x: dx, y: dy}
+}
+
+func (a *moveAction) start(target INode) {
+	a.target = target
+	a.startX = target.Position().X
+	a.startY = target.Position().Y
+
+	a.endX, a.endY = a.x, a.y
+	if a.relative {
+		a.endX += a.startX
+		a.endY += a.startY
+	}
+}
+
+func (a *moveAction) update(elapsed float64) {
+	p := a.progress(elapsed)
+	a.target.SetPositionBy2Comp(lerp(a.startX, a.endX, p), lerp(a.startY, a.endY, p))
+}
+
+// -----------------------------------------------------------------
+// Rotate
+// -----------------------------------------------------------------
+
+type rotateAction struct {
+	interval
+	relative bool
+	angle    float64
+
+	from, to float64
+}
+
+// NewRotateTo rotates a node to angle degrees.
+func NewRotateTo(duration, angle float64) IAction {
+	return &rotateAction{interval: interval{duration: duration}, angle: angle * DegreeToRadians}
+}
+
+// NewRotateBy rotates a node by angle degrees.
+func NewRotateBy(duration, angle float64) IAction {
+	return &rotateAction{interval: interval{duration: duration}, relative: true, angle: angle * DegreeToRadians}
+}
+
+func (a *rotateAction) start(target INode) {
+	a.target = target
+	a.from = target.Rotation()
+
+	a.to = a.angle
+	if a.relative {
+		a.to += a.from
+	}
+}
+
+func (a *rotateAction) update(elapsed float64) {
+	a.target.SetRotation(lerp(a.from, a.to, a.progress(elapsed)))
+}
+
+// -----------------------------------------------------------------
+// Scale
+// -----------------------------------------------------------------
+
+type scaleAction struct {
+	interval
+	sx, sy float64
+
+	fromX, fromY float64
+	scale        *Vector3
+}
+
+// NewScaleTo scales a node to sx,sy.
+func NewScaleTo(duration, sx, sy float64) IAction {
+	return &scaleAction{interval: interval{duration: duration}, sx: sx, sy: sy, scale: NewVector3()}
+}
+
+func (a *scaleAction) start(target INode) {
+	a.target = target
+	a.fromX = target.Scale().X
+	a.fromY = target.Scale().Y
+}
+
+func (a *scaleAction) update(elapsed float64) {
+	p := a.progress(elapsed)
+	a.scale.Set2Components(lerp(a.fromX, a.sx, p), lerp(a.fromY, a.sy, p))
+	a.target.SetScale(a.scale)
+}
+
+// -----------------------------------------------------------------
+// Fade and tint
+// -----------------------------------------------------------------
+
+type fadeAction struct {
+	interval
+	opacity float64
+	from    float64
+}
+
+// NewFadeTo fades a node, and so its subtree, to opacity.
+func NewFadeTo(duration, opacity float64) IAction {
+	return &fadeAction{interval: interval{duration: duration}, opacity: opacity}
+}
+
+func (a *fadeAction) start(target INode) {
+	a.target = target
+	a.from = target.Opacity()
+}
+
+func (a *fadeAction) update(elapsed float64) {
+	a.target.SetOpacity(lerp(a.from, a.opacity, a.progress(elapsed)))
+}
+
+type tintAction struct {
+	interval
+	tint color.RGBA
+	from color.RGBA
+}
+
+// NewTintTo tints a node, and so its subtree, to tint.
+func NewTintTo(duration float64, tint color.RGBA) IAction {
+	return &tintAction{interval: interval{duration: duration}, tint: tint}
+}
+
+func (a *tintAction) start(target INode) {
+	a.target = target
+	a.from = target.Tint()
+}
+
+func (a *tintAction) update(elapsed float64) {
+	a.target.SetTint(lerpColor(a.from, a.tint, a.progress(elapsed)))
+}
+
+func lerpColor(from, to color.RGBA, p float64) color.RGBA {
+	channel := func(f, t uint8) uint8 {
+		return uint8(math.Max(0.0, math.Min(math.Round(lerp(float64(f), float64(t), p)), 255.0)))
+	}
+	return color.RGBA{
+		R: channel(from.R, to.R),
+		G: channel(from.G, to.G),
+		B: channel(from.B, to.B),
+		A: channel(from.A, to.A),
+	}
+}
+
+// -----------------------------------------------------------------
+// Delay and call
+// -----------------------------------------------------------------
+
+type delayAction struct {
+	interval
+}
+
+// NewDelay does nothing for duration, typically within a Sequence.
+func NewDelay(duration float64) IAction {
+	return &delayAction{interval{duration: duration}}
+}
+
+func (a *delayAction) start(target INode) {
+	a.target = target
+}
+
+func (a *delayAction) update(elapsed float64) {
+}
+
+type callAction struct {
+	fn     func(target INode)
+	target INode
+	called bool
+}
+
+// NewCallFunc calls fn with the node, once per run.
+func NewCallFunc(fn func(target INode)) IAction {
+	return &callAction{fn: fn}
+}
+
+func (a *callAction) Duration() float64 {
+	return 0.0
+}
+
+func (a *callAction) start(target INode) {
+	a.target = target
+	a.called = false
+}
+
+func (a *callAction) update(elapsed float64) {
+	if !a.called {
+		a.called = true
+		a.fn(a.target)
+	}
+}
+
+// -----------------------------------------------------------------
+// Sequence and spawn
+// -----------------------------------------------------------------
+
+type sequenceAction struct {
+	actions []IAction
+	// Start time of each action
+	starts   []float64
+	duration float64
+
+	target  INode
+	current int
+}
+
+// NewSequence runs actions one after another.
+func NewSequence(actions ...IAction) IAction {
+	a := &sequenceAction{actions: actions}
+	for _, action := range actions {
+		a.starts = append(a.starts, a.duration)
+		a.duration += action.Duration()
+	}
+	return a
+}
+
+func (a *sequenceAction) Duration() float64 {
+	return a.duration
+}
+
+func (a *sequenceAction) start(target INode) {
+	a.target = target
+	a.current = -1
+}
+
+func (a *sequenceAction) update(elapsed float64) {
+	if len(a.actions) == 0 {
+		return
+	}
+
+	// Finish each action elapsed has passed and start the next
+	for a.current < 0 || (a.current < len(a.actions)-1 && elapsed >= a.starts[a.current+1]) {
+		if a.current >= 0 {
+			finished := a.actions[a.current]
+			finished.update(finished.Duration())
+		}
+		a.current++
+		a.actions[a.current].start(a.target)
+	}
+
+	action := a.actions[a.current]
+	action.update(math.Min(elapsed-a.starts[a.current], action.Duration()))
+}
+
+type spawnAction struct {
+	actions  []IAction
+	duration float64
+}
+
+// NewSpawn runs actions at the same time. It lasts as long as the
+// longest.
+func NewSpawn(actions ...IAction) IAction {
+	a := &spawnAction{actions: actions}
+	for _, action := range actions {
+		a.duration = math.Max(a.duration, action.Duration())
+	}
+	return a
+}
+
+func (a *spawnAction) Duration() float64 {
+	return a.duration
+}
+
+func (a *spawnAction) start(target INode) {
+	for _, action := range a.actions {
+		action.start(target)
+	}
+}
+
+func (a *spawnAction) update(elapsed float64) {
+	for _, action := range a.actions {
+		action.update(math.Min(elapsed, action.Duration()))
+	}
+}
+
+// -----------------------------------------------------------------
+// Repeat
+// -----------------------------------------------------------------
+
+type repeatAction struct {
+	action IAction
+	// RepeatForever or a count
+	times int
+
+	target    INode
+	iteration int
+}
+
+// NewRepeat runs action times times.
+func NewRepeat(action IAction, times int) IAction {
+	return &repeatAction{action: action, times: times}
+}
+
+// NewForever runs action over and over until cancelled.
+func NewForever(action IAction) IAction {
+	return &repeatAction{action: action, times: RepeatForever}
+}
+
+func (a *repeatAction) Duration() float64 {
+	if a.times == RepeatForever {
+		return math.Inf(1)
+	}
+	return a.action.Duration() * float64(a.times)
+}
+
+func (a *repeatAction) start(target INode) {
+	a.target = target
+	a.iteration = 0
+	a.action.start(target)
+}
+
+func (a *repeatAction) update(elapsed float64) {
+	d := a.action.Duration()
+
+	// An instant action repeats all its times at once, or once per
+	// update forever
+	if d <= 0.0 {
+		if a.times == RepeatForever {
+			a.action.update(0.0)
+			a.action.start(a.target)
+			return
+		}
+		for a.iteration < a.times {
+			a.action.update(0.0)
+			a.iteration++
+			if a.iteration < a.times {
+				a.action.start(a.target)
+			}
+		}
+		return
+	}
+
+	iteration := int(elapsed / d)
+	if a.times != RepeatForever && iteration > a.times-1 {
+		iteration = a.times - 1
+	}
+
+	for a.iteration < iteration {
+		a.action.update(d)
+		a.iteration++
+		a.action.start(a.target)
+	}
+
+	a.action.update(math.Min(elapsed-float64(a.iteration)*d, d))
+}
+
+// -----------------------------------------------------------------
+// Ease
+// -----------------------------------------------------------------
+
+type easeAction struct {
+	action IAction
+	easing EasingFunction
+}
+
+// NewEase runs action with its time mapped through easing, for example,
+// LinearEasing or any of the Penner easings.
+func NewEase(action IAction, easing EasingFunction) IAction {
+	return &easeAction{action: action, easing: easing}
+}
+
+func (a *easeAction) Duration() float64 {
+	return a.action.Duration()
+}
+
+func (a *easeAction) start(target INode) {
+	a.action.start(target)
+}
+
+func (a *easeAction) update(elapsed float64) {
+	d := a.action.Duration()
+	if d <= 0.0 || math.IsInf(d, 1) {
+		a.action.update(elapsed)
+		return
+	}
+	a.action.update(a.easing(elapsed, 0.0, d, d))
+}
@@ -0,0 +1,103 @@
+package engine
+
+// Clock controls how simulation time advances. It can pause the
+// simulation while rendering continues, single step while paused and
+// scale time globally for slow motion or fast forward.
+type Clock struct {
+	paused bool
+
+	// Steps still to run while paused
+	pendingSteps int
+
+	timeScale float64
+
+	// Scaled simulation time in seconds
+	time float64
+	// Number of simulation steps that have run
+	ticks int64
+}
+
+// NewClock creates a running clock with a time scale of 1.0
+func NewClock() *Clock {
+	c := new(Clock)
+	c.timeScale = 1.0
+	return c
+}
+
+// Pause stops the simulation. Rendering continues.
+func (c *Clock) Pause() {
+	c.paused = true
+}
+
+// Resume restarts the simulation and discards any pending steps.
+func (c *Clock) Resume() {
+	c.paused = false
+	c.pendingSteps = 0
+}
+
+// TogglePause flips between paused and running.
+func (c *Clock) TogglePause() {
+	if c.paused {
+		c.Resume()
+	} else {
+		c.Pause()
+	}
+}
+
+// IsPaused is true while the simulation is paused.
+func (c *Clock) IsPaused() bool {
+	return c.paused
+}
+
+// Step runs n simulation steps and then pauses. If the clock is running
+// it is paused first.
+func (c *Clock) Step(n int) {
+	c.paused = true
+	c.pendingSteps += n
+}
+
+// PendingSteps returns how many single steps are still to run.
+func (c *Clock) PendingSteps() int {
+	return c.pendingSteps
+}
+
+// SetTimeScale scales every step, for example, 0.5 is half speed and
+// 2.0 is double speed.
+func (c *Clock) SetTimeScale(scale float64) {
+	if scale < 0.0 {
+		scale = 0.0
+	}
+	c.timeScale = scale
+}
+
+// TimeScale returns the global time scale.
+func (c *Clock) TimeScale() float64 {
+	return c.timeScale
+}
+
+// Time returns the scaled simulation time in seconds.
+func (c *Clock) Time() float64 {
+	return c.time
+}
+
+// Ticks returns how many simulation steps have run.
+func (c *Clock) Ticks() int64 {
+	return c.ticks
+}
+
+// advance is called for each fixed step of dt seconds. It returns the
+// scaled dt and whether the simulation should update this step.
+func (c *Clock) advance(dt float64) (float64, bool) {
+	if c.paused {
+		if c.pendingSteps == 0 {
+			return 0.0, false
+		}
+		c.pendingSteps--
+	}
+
+	sdt := dt * c.timeScale
+	c.time += sdt
+	c.ticks++
+
+	return sdt, true
+}
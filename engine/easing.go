@@ -0,0 +1,426 @@
+package engine
+
+import "math"
+
+// Robert Penner's easing equations in the same t,b,c,d form as
+// LinearEasing: t is the time position from 0 to d, b the start value and
+// c the change in value. In eases start slowly, Out eases end slowly and
+// InOut eases do both. A duration of zero or less jumps to the end value.
+
+// BackOvershoot is how far the Back easings overshoot, 10%.
+const BackOvershoot = 1.70158
+
+// EaseInQuad accelerates from zero velocity.
+func EaseInQuad(t, b, c, d float64) float64 {
+	if d <= 0.0 {
+		return b + c
+	}
+	t /= d
+	return c*t*t + b
+}
+
+// EaseOutQuad decelerates to zero velocity.
+func EaseOutQuad(t, b, c, d float64) float64 {
+	if d <= 0.0 {
+		return b + c
+	}
+	t /= d
+	return -c*t*(t-2.0) + b
+}
+
+// EaseInOutQuad accelerates until halfway, then decelerates.
+func EaseInOutQuad(t, b, c, d float64) float64 {
+	if d <= 0.0 {
+		return b + c
+	}
+	t /= d / 2.0
+	if t < 1.0 {
+		return c/2.0*t*t + b
+	}
+	t--
+	return -c/2.0*(t*(t-2.0)-1.0) + b
+}
+
+// EaseInCubic accelerates from zero velocity.
+func EaseInCubic(t, b, c, d float64) float64 {
+	if d <= 0.0 {
+		return b + c
+	}
+	t /= d
+	return c*t*t*t + b
+}
+
+// EaseOutCubic decelerates to zero velocity.
+func EaseOutCubic(t, b, c, d float64) float64 {
+	if d <= 0.0 {
+		return b + c
+	}
+	t = t/d - 1.0
+	return c*(t*t*t+1.0) + b
+}
+
+// EaseInOutCubic accelerates until halfway, then decelerates.
+func EaseInOutCubic(t, b, c, d float64) float64 {
+	if d <= 0.0 {
+		return b + c
+	}
+	t /= d / 2.0
+	if t < 1.0 {
+		return c/2.0*t*t*t + b
+	}
+	t -= 2.0
+	return c/2.0*(t*t*t+2.0) + b
+}
+
+// EaseInQuart accelerates from zero velocity.
+func EaseInQuart(t, b, c, d float64) float64 {
+	if d <= 0.0 {
+		return b + c
+	}
+	t /= d
+	return c*t*t*t*t + b
+}
+
+// EaseOutQuart decelerates to zero velocity.
+func EaseOutQuart(t, b, c, d float64) float64 {
+	if d <= 0.0 {
+		return b + c
+	}
+	t = t/d - 1.0
+	return -c*(t*t*t*t-1.0) + b
+}
+
+// EaseInOutQuart accelerates until halfway, then decelerates.
+func EaseInOutQuart(t, b, c, d float64) float64 {
+	if d <= 0.0 {
+		return b + c
+	}
+	t /= d / 2.0
+	if t < 1.0 {
+		return c/2.0*t*t*t*t + b
+	}
+	t -= 2.0
+	return -c/2.0*(t*t*t*t-2.0) + b
+}
+
+// EaseInQuint accelerates from zero velocity.
+func EaseInQuint(t, b, c, d float64) float64 {
+	if d <= 0.0 {
+		return b + c
+	}
+	t /= d
+	return c*t*t*t*t*t + b
+}
+
+// EaseOutQuint decelerates to zero velocity.
+func EaseOutQuint(t, b, c, d float64) float64 {
+	if d <= 0.0 {
+		return b + c
+	}
+	t = t/d - 1.0
+	return c*(t*t*t*t*t+1.0) + b
+}
+
+// EaseInOutQuint accelerates until halfway, then decelerates.
+func EaseInOutQuint(t, b, c, d float64) float64 {
+	if d <= 0.0 {
+		return b + c
+	}
+	t /= d / 2.0
+	if t < 1.0 {
+		return c/2.0*t*t*t*t*t + b
+	}
+	t -= 2.0
+	return c/2.0*(t*t*t*t*t+2.0) + b
+}
+
+// EaseInSine accelerates from zero velocity.
+func EaseInSine(t, b, c, d float64) float64 {
+	if d <= 0.0 {
+		return b + c
+	}
+	return -c*math.Cos(t/d*(math.Pi/2.0)) + c + b
+}
+
+// EaseOutSine decelerates to zero velocity.
+func EaseOutSine(t, b, c, d float64) float64 {
+	if d <= 0.0 {
+		return b + c
+	}
+	return c*math.Sin(t/d*(math.Pi/2.0)) + b
+}
+
+// EaseInOutSine accelerates until halfway, then decelerates.
+func EaseInOutSine(t, b, c, d float64) float64 {
+	if d <= 0.0 {
+		return b + c
+	}
+	return -c/2.0*(math.Cos(math.Pi*t/d)-1.0) + b
+}
+
+// EaseInExpo accelerates exponentially from zero velocity.
+func EaseInExpo(t, b, c, d float64) float64 {
+	if d <= 0.0 {
+		return b + c
+	}
+	if t == 0.0 {
+		return b
+	}
+	return c*math.Pow(2.0, 10.0*(t/d-1.0)) + b
+}
+
+// EaseOutExpo decelerates exponentially to zero velocity.
+func EaseOutExpo(t, b, c, d float64) float64 {
+	if d <= 0.0 {
+		return b + c
+	}
+	if t == d {
+		return b + c
+	}
+	return c*(-math.Pow(2.0, -10.0*t/d)+1.0) + b
+}
+
+// EaseInOutExpo accelerates exponentially until halfway, then
+// decelerates.
+func EaseInOutExpo(t, b, c, d float64) float64 {
+	if d <= 0.0 {
+		return b + c
+	}
+	if t == 0.0 {
+		return b
+	}
+	if t == d {
+		return b + c
+	}
+	t /= d / 2.0
+	if t < 1.0 {
+		return c/2.0*math.Pow(2.0, 10.0*(t-1.0)) + b
+	}
+	t--
+	return c/2.0*(-math.Pow(2.0, -10.0*t)+2.0) + b
+}
+
+// EaseInCirc accelerates along a circular curve.
+func EaseInCirc(t, b, c, d float64) float64 {
+	if d <= 0.0 {
+		return b + c
+	}
+	t /= d
+	return -c*(math.Sqrt(1.0-t*t)-1.0) + b
+}
+
+// EaseOutCirc decelerates along a circular curve.
+func EaseOutCirc(t, b, c, d float64) float64 {
+	if d <= 0.0 {
+		return b + c
+	}
+	t = t/d - 1.0
+	return c*math.Sqrt(1.0-t*t) + b
+}
+
+// EaseInOutCirc accelerates until halfway, then decelerates.
+func EaseInOutCirc(t, b, c, d float64) float64 {
+	if d <= 0.0 {
+		return b + c
+	}
+	t /= d / 2.0
+	if t < 1.0 {
+		return -c/2.0*(math.Sqrt(1.0-t*t)-1.0) + b
+	}
+	t -= 2.0
+	return c/2.0*(math.Sqrt(1.0-t*t)+1.0) + b
+}
+
+// EaseInBack backs up slightly before moving to the end value.
+func EaseInBack(t, b, c, d float64) float64 {
+	if d <= 0.0 {
+		return b + c
+	}
+	s := BackOvershoot
+	t /= d
+	return c*t*t*((s+1.0)*t-s) + b
+}
+
+// EaseOutBack overshoots the end value then settles back onto it.
+func EaseOutBack(t, b, c, d float64) float64 {
+	if d <= 0.0 {
+		return b + c
+	}
+	s := BackOvershoot
+	t = t/d - 1.0
+	return c*(t*t*((s+1.0)*t+s)+1.0) + b
+}
+
+// EaseInOutBack backs up at the start and overshoots at the end.
+func EaseInOutBack(t, b, c, d float64) float64 {
+	if d <= 0.0 {
+		return b + c
+	}
+	s := BackOvershoot * 1.525
+	t /= d / 2.0
+	if t < 1.0 {
+		return c/2.0*(t*t*((s+1.0)*t-s)) + b
+	}
+	t -= 2.0
+	return c/2.0*(t*t*((s+1.0)*t+s)+2.0) + b
+}
+
+// EaseInElastic oscillates with growing amplitude, like a plucked
+// string in reverse.
+func EaseInElastic(t, b, c, d float64) float64 {
+	if d <= 0.0 {
+		return b + c
+	}
+	if t == 0.0 {
+		return b
+	}
+	t /= d
+	if t == 1.0 {
+		return b + c
+	}
+	p := d * 0.3
+	s := p / 4.0
+	t--
+	return -(c * math.Pow(2.0, 10.0*t) * math.Sin((t*d-s)*(2.0*math.Pi)/p)) + b
+}
+
+// EaseOutElastic overshoots and oscillates onto the end value.
+func EaseOutElastic(t, b, c, d float64) float64 {
+	if d <= 0.0 {
+		return b + c
+	}
+	if t == 0.0 {
+		return b
+	}
+	t /= d
+	if t == 1.0 {
+		return b + c
+	}
+	p := d * 0.3
+	s := p / 4.0
+	return c*math.Pow(2.0, -10.0*t)*math.Sin((t*d-s)*(2.0*math.Pi)/p) + c + b
+}
+
+// EaseInOutElastic oscillates at both ends.
+func EaseInOutElastic(t, b, c, d float64) float64 {
+	if d <= 0.0 {
+		return b + c
+	}
+	if t == 0.0 {
+		return b
+	}
+	t /= d / 2.0
+	if t == 2.0 {
+		return b + c
+	}
+	p := d * (0.3 * 1.5)
+	s := p / 4.0
+	t--
+	if t < 0.0 {
+		return -0.5*(c*math.Pow(2.0, 10.0*t)*math.Sin((t*d-s)*(2.0*math.Pi)/p)) + b
+	}
+	return c*math.Pow(2.0, -10.0*t)*math.Sin((t*d-s)*(2.0*math.Pi)/p)*0.5 + c + b
+}
+
+// EaseOutBounce bounces onto the end value like a dropped ball.
+func EaseOutBounce(t, b, c, d float64) float64 {
+	if d <= 0.0 {
+		return b + c
+	}
+	t /= d
+	switch {
+	case t < 1.0/2.75:
+		return c*(7.5625*t*t) + b
+	case t < 2.0/2.75:
+		t -= 1.5 / 2.75
+		return c*(7.5625*t*t+0.75) + b
+	case t < 2.5/2.75:
+		t -= 2.25 / 2.75
+		return c*(7.5625*t*t+0.9375) + b
+	default:
+		t -= 2.625 / 2.75
+		return c*(7.5625*t*t+0.984375) + b
+	}
+}
+
+// EaseInBounce bounces away from the start value.
+func EaseInBounce(t, b, c, d float64) float64 {
+	if d <= 0.0 {
+		return b + c
+	}
+	return c - EaseOutBounce(d-t, 0.0, c, d) + b
+}
+
+// EaseInOutBounce bounces at both ends.
+func EaseInOutBounce(t, b, c, d float64) float64 {
+	if d <= 0.0 {
+		return b + c
+	}
+	if t < d/2.0 {
+		return EaseInBounce(t*2.0, 0.0, c, d)*0.5 + b
+	}
+	return EaseOutBounce(t*2.0-d, 0.0, c, d)*0.5 + c*0.5 + b
+}
+
+// NewCubicBezierEasing returns an easing following the curve from 0,0 to
+// 1,1 with control points x1,y1 and x2,y2, as CSS's cubic-bezier() does.
+// x1 and x2 must be within 0 to 1; y1 and y2 can go outside it to
+// overshoot.
+func NewCubicBezierEasing(x1, y1, x2, y2 float64) EasingFunction {
+	// Polynomial coefficients of each axis
+	cx := 3.0 * x1
+	bx := 3.0*(x2-x1) - cx
+	ax := 1.0 - cx - bx
+
+	cy := 3.0 * y1
+	by := 3.0*(y2-y1) - cy
+	ay := 1.0 - cy - by
+
+	sampleX := func(u float64) float64 { return ((ax*u+bx)*u + cx) * u }
+	sampleY := func(u float64) float64 { return ((ay*u+by)*u + cy) * u }
+	slopeX := func(u float64) float64 { return (3.0*ax*u+2.0*bx)*u + cx }
+
+	// solve finds the curve parameter whose x is x
+	solve := func(x float64) float64 {
+		// Newton-Raphson converges quickly unless the slope is flat
+		u := x
+		for i := 0; i < 8; i++ {
+			dx := sampleX(u) - x
+			if math.Abs(dx) < Epsilon {
+				return u
+			}
+			slope := slopeX(u)
+			if math.Abs(slope) < 1e-6 {
+				break
+			}
+			u -= dx / slope
+		}
+
+		// Fall back to bisection which always converges
+		lo, hi := 0.0, 1.0
+		u = x
+		for i := 0; i < 50 && hi-lo > Epsilon; i++ {
+			if sampleX(u) < x {
+				lo = u
+			} else {
+				hi = u
+			}
+			u = (lo + hi) / 2.0
+		}
+		return u
+	}
+
+	return func(t, b, c, d float64) float64 {
+		if d <= 0.0 {
+			return b + c
+		}
+		x := t / d
+		if x <= 0.0 {
+			return b
+		}
+		if x >= 1.0 {
+			return b + c
+		}
+		return c*sampleY(solve(x)) + b
+	}
+}
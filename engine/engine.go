@@ -1,10 +1,8 @@
 package engine
 
 import (
-	"fmt"
 	"image"
 	"image/color"
-	"log"
 	"math"
 	"time"
 
@@ -12,9 +10,6 @@ import (
 )
 
 const (
-	fps         = 60.0
-	framePeriod = 1.0 / fps * 1000.0
-
 	// Epsilon = 0.00001
 	Epsilon = 0.00001 // ~32 bits
 
@@ -38,8 +33,13 @@ type Matrix4 struct {
 
 // Game should be implemented by the developer
 type Game interface {
-	Update(float64, []uint8)
-	Render(*image.RGBA)
+	// Update is called once per fixed simulation step with the step
+	// size in seconds. Input can be queried for held keys and for keys
+	// pressed or released since the previous step.
+	Update(float64, *Input)
+	// Render is called once per frame with the interpolation alpha
+	// between the previous and current simulation step.
+	Render(*image.RGBA, float64)
 }
 
 // AffinePool is a pool of transforms
@@ -50,10 +50,7 @@ var VectorsPool = NewVectorPool(100)
 
 // Engine is the GUI and shell
 type Engine struct {
-	window   *sdl.Window
-	surface  *sdl.Surface
-	renderer *sdl.Renderer
-	texture  *sdl.Texture
+	platform Platform
 
 	Width  int32
 	Height int32
@@ -62,8 +59,13 @@ type Engine struct {
 
 	game Game
 
-	// Scene graph root which is always a GroupNode
+	// Scene graph root which is always a GroupNode. It is used when
+	// no scene is running.
 	root IGroupNode
+	// Scene stack and transitions
+	scenes *SceneManager
+	// Actions run on nodes, see actions.go
+	actions *ActionManager
 
 	// drawing buffer
 	pixels *image.RGBA
@@ -71,32 +73,68 @@ type Engine struct {
 
 	context *RenderContext
 
-	// mouse
-	mx int32
-	my int32
+	// Converts real frame time into fixed simulation steps
+	timestep *FixedTimestep
+	// Pauses, single steps and scales simulation time
+	clock *Clock
+
+	// Frame pacing, see frame_rate.go
+	targetFPS float64
+	frameMode FrameMode
+	idleFPS   float64
+	focused   bool
+
+	// Typed input dispatch and state
+	input *Input
+	// Events posted by the platform, dispatched once per frame
+	events []IEvent
+
+	// Delivers pointer events to scene graph nodes
+	pointer *PointerDispatcher
+
+	// Input recording and playback, see replay.go
+	recorder *Recorder
+	replay   *Replay
 
 	running bool
 
 	opened bool
 
-	nFont        *Font
-	txtSimStatus *Text
-	txtFPSLabel  *Text
-	txtLoopLabel *Text
-	txtMousePos  *Text
-	dynaTxt      *DynaText
+	nFont *Font
+	stats FrameStats
 }
 
-// NewEngine creates a new engine and initializes it.
+// NewEngine creates a new engine backed by an SDL window.
 func NewEngine(width, height int32) *Engine {
+	return NewEngineWithPlatform(width, height, NewSDLPlatform())
+}
+
+// NewEngineWithPlatform creates a new engine that runs on the given platform,
+// for example, a HeadlessPlatform.
+func NewEngineWithPlatform(width, height int32, platform Platform) *Engine {
 	v := new(Engine)
+	v.platform = platform
 	v.Width = width
 	v.Height = height
 	v.opened = false
 	v.ClearColor = color.RGBA{127, 127, 127, 255}
 
+	v.targetFPS = DefaultFPS
+	v.frameMode = FrameModeCapped
+	v.focused = true
+
+	v.timestep = NewFixedTimestep(DefaultTickRate)
+	v.clock = NewClock()
+
+	v.input = NewInput()
+	v.input.AddListener(PriorityEngine, v.handleEvent)
+	v.pointer = NewPointerDispatcher(v.input, v.GetRoot)
+
 	v.root = NewGroupNode(nil, false)
 	v.root.SetName("Root")
+	v.scenes = NewSceneManager()
+	v.scenes.idle = v.root
+	v.actions = NewActionManager()
 
 	return v
 }
@@ -107,14 +145,72 @@ func (v *Engine) Initialize(title string) {
 	v.opened = true
 }
 
+// SetRoot replaces the root used when no scene is running. If the old
+// root is running it exits and the new root enters.
 func (v *Engine) SetRoot(n *GroupNode) {
+	if v.root.IsRunning() {
+		exitTree(v.root)
+		enterTree(n)
+	}
 	v.root = n
+	v.scenes.idle = n
 }
 
+// GetRoot returns the running scene's root or, if there is no running
+// scene, the root set with SetRoot.
 func (v *Engine) GetRoot() IGroupNode {
+	if s := v.scenes.Running(); s != nil {
+		return s.Root()
+	}
 	return v.root
 }
 
+// Scenes returns the scene manager used to push, pop and replace scenes.
+func (v *Engine) Scenes() *SceneManager {
+	return v.scenes
+}
+
+// Actions returns the manager used to run actions on nodes.
+func (v *Engine) Actions() *ActionManager {
+	return v.actions
+}
+
+// Platform returns the backend the engine runs on.
+func (v *Engine) Platform() Platform {
+	return v.platform
+}
+
+// Pixels returns the drawing buffer.
+func (v *Engine) Pixels() *image.RGBA {
+	return v.pixels
+}
+
+// Timestep returns the fixed step configuration, for example, to change
+// the tick rate.
+func (v *Engine) Timestep() *FixedTimestep {
+	return v.timestep
+}
+
+// Clock returns the simulation clock used to pause, step and scale time.
+func (v *Engine) Clock() *Clock {
+	return v.clock
+}
+
+// Input returns the input dispatcher, for example, to add listeners.
+func (v *Engine) Input() *Input {
+	return v.input
+}
+
+// Pointer returns the dispatcher delivering pointer events to nodes.
+func (v *Engine) Pointer() *PointerDispatcher {
+	return v.pointer
+}
+
+// SetGame sets the game without starting the loop. Use this with RunFrames.
+func (v *Engine) SetGame(game Game) {
+	v.game = game
+}
+
 // Start shows the display and begins event polling
 func (v *Engine) Start(game Game) {
 	v.game = game
@@ -129,38 +225,39 @@ func (v *Engine) SetFont(fontPath string, size int) error {
 	return err
 }
 
-// filterEvent returns false if it handled the event. Returning false
-// prevents the event from being added to the queue.
-func (v *Engine) filterEvent(e sdl.Event, userdata interface{}) bool {
-	switch t := e.(type) {
-	case *sdl.QuitEvent:
+// postEvent is called by the platform for each event it polls. Events
+// are dispatched together once the platform has finished pumping.
+func (v *Engine) postEvent(event IEvent) {
+	v.events = append(v.events, event)
+}
+
+// dispatchEvents passes the frame's events through the input listeners.
+func (v *Engine) dispatchEvents() {
+	for i, event := range v.events {
+		v.input.Dispatch(event)
+		v.events[i] = nil
+	}
+	v.events = v.events[:0]
+}
+
+// handleEvent is the engine's own, lowest priority, listener. A game can
+// consume ESC or a quit request to prevent the engine stopping.
+func (v *Engine) handleEvent(event IEvent) {
+	switch t := event.(type) {
+	case *QuitEvent:
 		v.running = false
-		return false // We handled it. Don't allow it to be added to the queue.
-	case *sdl.MouseMotionEvent:
-		v.mx = t.X
-		v.my = t.Y
-		// fmt.Printf("[%d ms] MouseMotion\ttype:%d\tid:%d\tx:%d\ty:%d\txrel:%d\tyrel:%d\n",
-		// 	t.Timestamp, t.Type, t.Which, t.X, t.Y, t.XRel, t.YRel)
-		return false // We handled it. Don't allow it to be added to the queue.
-		// case *sdl.MouseButtonEvent:
-		// 	fmt.Printf("[%d ms] MouseButton\ttype:%d\tid:%d\tx:%d\ty:%d\tbutton:%d\tstate:%d\n",
-		// 		t.Timestamp, t.Type, t.Which, t.X, t.Y, t.Button, t.State)
-		// case *sdl.MouseWheelEvent:
-		// 	fmt.Printf("[%d ms] MouseWheel\ttype:%d\tid:%d\tx:%d\ty:%d\n",
-		// 		t.Timestamp, t.Type, t.Which, t.X, t.Y)
-	case *sdl.KeyboardEvent:
-		if t.State == sdl.PRESSED {
-			switch t.Keysym.Scancode {
-			case sdl.SCANCODE_ESCAPE:
-				v.running = false
-			}
+	case *KeyEvent:
+		if t.Type() == EventKeyDown && t.Scancode == sdl.SCANCODE_ESCAPE {
+			v.running = false
+		}
+	case *WindowEvent:
+		switch t.Kind {
+		case WindowFocusGained:
+			v.onFocus(true)
+		case WindowFocusLost:
+			v.onFocus(false)
 		}
-		// fmt.Printf("[%d ms] Keyboard\ttype:%d\tsym:%c\tmodifiers:%d\tstate:%d\trepeat:%d\n",
-		// 	t.Timestamp, t.Type, t.Keysym.Sym, t.Keysym.Mod, t.State, t.Repeat)
-		return false
 	}
-
-	return true
 }
 
 // Run starts the polling event loop. This must run on
@@ -172,70 +269,121 @@ func (v *Engine) Run() {
 	var loopTime float64
 
 	sleepDelay := 0.0
-
-	// Get a reference to SDL's internal keyboard state. It is updated
-	// during sdl.PollEvent()
-	keyState := sdl.GetKeyboardState()
-
-	sdl.SetEventFilterFunc(v.filterEvent, nil)
+	previousStart := time.Now()
 
 	for v.running {
 		frameStart = time.Now()
 
-		sdl.PumpEvents()
+		// Real time since the previous frame started, in milliseconds
+		elapsedTime = float64(frameStart.Sub(previousStart).Nanoseconds()) / 1000000.0
+		previousStart = frameStart
 
-		dt := elapsedTime / 1000.0
+		v.frame(elapsedTime, loopTime)
 
-		// Update the scene graph
-		v.root.Update(dt)
+		loopTime = float64(time.Since(frameStart).Nanoseconds()) / 1000000.0
 
-		// Notify external clients of an update, perhaps for key events
-		v.game.Update(dt, keyState)
+		// Lock frame rate, unless uncapped or synced to the display
+		framePeriod := v.FramePeriod()
+		if framePeriod > 0 {
+			sleepDelay = math.Floor(framePeriod - loopTime)
+			if sleepDelay > 0 {
+				// fmt.Printf("%3.5f ,%3.5f, %3.5f, %3.5f \n", framePeriod, elapsedTime, sleepDelay, loopTime)
+				v.platform.Delay(uint32(sleepDelay))
+			}
+		}
+	}
+}
 
-		v.clearDisplay()
+// RunFrames runs n frames back to back without any frame rate locking,
+// each feeding exactly one target frame period into the fixed timestep.
+// It stops early if the engine is asked to quit. This is typically used
+// with a HeadlessPlatform.
+func (v *Engine) RunFrames(n int) {
+	v.running = true
 
-		// Render scene graph
-		v.root.Render(v.context)
+	for i := 0; i < n && v.running; i++ {
+		v.frame(1000.0/v.targetFPS, 0.0)
+	}
+}
 
-		// Notify external clients for any additional rendering
-		v.game.Render(v.pixels)
+// enterRoot enters the engine's own root at the start of each frame that
+// has no scene to run instead. The scene manager exits it when the first
+// scene is pushed.
+func (v *Engine) enterRoot() {
+	if v.scenes.Len() == 0 {
+		enterTree(v.root)
+	}
+}
+
+// IsRunning is true while the loop is active.
+func (v *Engine) IsRunning() bool {
+	return v.running
+}
 
-		v.renderRawOverlay(elapsedTime, loopTime)
+// frame runs a single iteration of the loop: input, update, render and
+// present.
+func (v *Engine) frame(elapsedTime, loopTime float64) {
+	v.enterRoot()
+	v.platform.PumpEvents(v)
 
-		v.renderer.Present()
+	if v.replay != nil {
+		elapsedTime = v.replayFrame(elapsedTime)
+	}
 
-		loopTime = float64(time.Since(frameStart).Nanoseconds() / 1000000.0)
+	if v.recorder != nil {
+		v.recordFrame(elapsedTime)
+	}
 
-		// Lock frame rate
-		sleepDelay = math.Floor(framePeriod - loopTime)
-		if sleepDelay > 0 {
-			// fmt.Printf("%3.5f ,%3.5f, %3.5f, %3.5f \n", framePeriod, elapsedTime, sleepDelay, loopTime)
-			sdl.Delay(uint32(sleepDelay))
-			elapsedTime = framePeriod
-		} else {
-			elapsedTime = framePeriod
-		}
+	v.dispatchEvents()
+
+	// Run zero or more fixed steps to consume the real elapsed time
+	alpha := v.timestep.Advance(elapsedTime/1000.0, v.step)
+
+	v.clearDisplay()
+
+	v.context.SetInterpolationAlpha(alpha)
+
+	// Render scene graph
+	if v.scenes.Len() > 0 {
+		v.scenes.render(v.context)
+	} else {
+		renderTree(v.context, v.root)
 	}
+
+	// Notify external clients for any additional rendering
+	if v.game != nil {
+		v.game.Render(v.pixels, alpha)
+	}
+
+	v.stats.ElapsedTime = elapsedTime
+	v.stats.LoopTime = loopTime
+	v.stats.MouseX, v.stats.MouseY = v.input.MousePosition()
+
+	v.platform.Present(v.pixels, &v.stats)
 }
 
-func (v *Engine) renderRawOverlay(elapsedTime, loopTime float64) {
-	// v.texture.Update(nil, v.pixels, v.pixelPitch)
-	// This takes on average 5-7ms
-	v.texture.Update(nil, v.pixels.Pix, v.pixels.Stride)
-	v.renderer.Copy(v.texture, nil, nil)
+// step advances the simulation by one fixed step of dt seconds. While
+// the clock is paused the scene graph isn't updated but the game still
+// is, with a dt of zero, so it can respond to input, for example, to
+// resume.
+func (v *Engine) step(dt float64) {
+	// Transitions run in unscaled time so they complete while paused
+	v.scenes.advance(dt)
+
+	dt, update := v.clock.advance(dt)
 
-	v.txtFPSLabel.DrawAt(10, 10)
-	f := fmt.Sprintf("%2.2f", 1.0/elapsedTime*1000.0)
-	v.dynaTxt.DrawAt(v.txtFPSLabel.Bounds.W+10, 10, f)
+	// Update the scene graph
+	if update {
+		v.GetRoot().Update(dt)
+	}
 
-	// v.mx, v.my, _ = sdl.GetMouseState()
-	v.txtMousePos.DrawAt(10, 25)
-	f = fmt.Sprintf("<%d, %d>", v.mx, v.my)
-	v.dynaTxt.DrawAt(v.txtMousePos.Bounds.W+10, 25, f)
+	// Notify external clients of an update, perhaps for key events
+	if v.game != nil {
+		v.game.Update(dt, v.input)
+	}
 
-	v.txtLoopLabel.DrawAt(10, 40)
-	f = fmt.Sprintf("%2.2f", loopTime)
-	v.dynaTxt.DrawAt(v.txtLoopLabel.Bounds.W+10, 40, f)
+	// Pressed/released edges are only visible to one step
+	v.input.endStep()
 }
 
 // Quit stops the engine from running, effectively shutting it down.
@@ -249,66 +397,16 @@ func (v *Engine) Close() {
 	if !v.opened {
 		return
 	}
-	var err error
-
-	v.nFont.Destroy()
-	v.txtFPSLabel.Destroy()
-	v.txtMousePos.Destroy()
-	v.dynaTxt.Destroy()
-
-	log.Println("Destroying texture")
-	err = v.texture.Destroy()
-	if err != nil {
-		log.Fatal(err)
-	}
 
-	log.Println("Destroying renderer")
-	v.renderer.Destroy()
-	if err != nil {
-		log.Fatal(err)
+	if v.nFont != nil {
+		v.nFont.Destroy()
 	}
 
-	log.Println("Destroying window")
-	err = v.window.Destroy()
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	sdl.Quit()
-
-	if err != nil {
-		log.Fatal(err)
-	}
+	v.platform.Close()
 }
 
 func (v *Engine) initialize(title string) {
-	var err error
-
-	err = sdl.Init(sdl.INIT_TIMER | sdl.INIT_VIDEO | sdl.INIT_EVENTS)
-	if err != nil {
-		panic(err)
-	}
-
-	v.window, err = sdl.CreateWindow(title, 100, 100,
-		v.Width, v.Height, sdl.WINDOW_SHOWN)
-
-	if err != nil {
-		panic(err)
-	}
-
-	// Using GetSurface requires using window.UpdateSurface() rather than renderer.Present.
-	// v.surface, err = v.window.GetSurface()
-	// if err != nil {
-	// 	panic(err)
-	// }
-	// v.renderer, err = sdl.CreateSoftwareRenderer(v.surface)
-	// OR create renderer manually
-	v.renderer, err = sdl.CreateRenderer(v.window, -1, sdl.RENDERER_SOFTWARE)
-	if err != nil {
-		panic(err)
-	}
-
-	v.texture, err = v.renderer.CreateTexture(sdl.PIXELFORMAT_ABGR8888, sdl.TEXTUREACCESS_STREAMING, v.Width, v.Height)
+	err := v.platform.Initialize(title, v.Width, v.Height)
 	if err != nil {
 		panic(err)
 	}
@@ -321,39 +419,11 @@ func (v *Engine) initialize(title string) {
 
 // Configure view with draw objects
 func (v *Engine) Configure() {
-	// rect := sdl.Rect{X: 0, Y: 0, W: 200, H: 200}
-	// v.renderer.SetDrawColor(255, 127, 0, 255)
-	// v.renderer.FillRect(&rect)
-
-	v.txtSimStatus = NewText(v.nFont, v.renderer)
-	err := v.txtSimStatus.SetText("Sim Status: ", sdl.Color{R: 0, G: 0, B: 255, A: 255})
+	err := v.platform.Configure(v.nFont)
 	if err != nil {
 		v.Close()
 		panic(err)
 	}
-
-	v.txtFPSLabel = NewText(v.nFont, v.renderer)
-	err = v.txtFPSLabel.SetText("FPS: ", sdl.Color{R: 200, G: 200, B: 200, A: 255})
-	if err != nil {
-		v.Close()
-		panic(err)
-	}
-
-	v.txtMousePos = NewText(v.nFont, v.renderer)
-	err = v.txtMousePos.SetText("Mouse: ", sdl.Color{R: 255, G: 127, B: 0, A: 255})
-	if err != nil {
-		v.Close()
-		panic(err)
-	}
-
-	v.txtLoopLabel = NewText(v.nFont, v.renderer)
-	err = v.txtLoopLabel.SetText("Loop: ", sdl.Color{R: 255, G: 127, B: 0, A: 255})
-	if err != nil {
-		v.Close()
-		panic(err)
-	}
-
-	v.dynaTxt = NewDynaText(v.nFont, v.renderer, sdl.Color{R: 255, G: 255, B: 255, A: 255})
 }
 
 func (v *Engine) clearDisplay() {
@@ -0,0 +1,101 @@
+package engine
+
+import (
+	"errors"
+	"math"
+)
+
+const (
+	// DefaultFPS is the target frame rate of a new engine.
+	DefaultFPS = 60.0
+)
+
+// FrameMode selects how the engine paces frames.
+type FrameMode int
+
+const (
+	// FrameModeCapped sleeps between frames to hold the target frame rate.
+	FrameModeCapped FrameMode = iota
+	// FrameModeUncapped runs frames as fast as possible, typically for
+	// benchmarking.
+	FrameModeUncapped
+	// FrameModeVSync lets the platform's present block until the display's
+	// vertical sync. It must be selected before Initialize.
+	FrameModeVSync
+)
+
+// ErrVSyncAfterInitialize is returned when vsync is changed on a platform
+// whose renderer has already been created.
+var ErrVSyncAfterInitialize = errors.New("vsync must be configured before Initialize")
+
+// ErrInvalidFPS is returned when a target frame rate isn't positive.
+var ErrInvalidFPS = errors.New("target frame rate must be positive")
+
+// SetTargetFPS sets the frame rate used by FrameModeCapped. It is also the
+// rate RunFrames feeds the fixed timestep at, so it must be positive; other
+// values are rejected and the current rate kept.
+func (v *Engine) SetTargetFPS(fps float64) error {
+	if !(fps > 0.0) || math.IsInf(fps, 1) {
+		return ErrInvalidFPS
+	}
+
+	v.targetFPS = fps
+	return nil
+}
+
+// TargetFPS returns the frame rate used by FrameModeCapped.
+func (v *Engine) TargetFPS() float64 {
+	return v.targetFPS
+}
+
+// SetFrameMode selects capped, uncapped or vsync pacing.
+func (v *Engine) SetFrameMode(mode FrameMode) error {
+	err := v.platform.SetVSync(mode == FrameModeVSync)
+	if err != nil {
+		return err
+	}
+
+	v.frameMode = mode
+	return nil
+}
+
+// FrameMode returns the current pacing mode.
+func (v *Engine) FrameMode() FrameMode {
+	return v.frameMode
+}
+
+// SetIdleFPS sets the low-power frame rate used while the window is
+// unfocused, regardless of the frame mode. Zero disables idle throttling.
+func (v *Engine) SetIdleFPS(fps float64) {
+	v.idleFPS = fps
+}
+
+// IdleFPS returns the low-power frame rate.
+func (v *Engine) IdleFPS() float64 {
+	return v.idleFPS
+}
+
+// IsFocused is true while the window has input focus.
+func (v *Engine) IsFocused() bool {
+	return v.focused
+}
+
+// FramePeriod returns the time in milliseconds the loop aims to spend per
+// frame. Zero means the loop does not sleep, either because it is
+// uncapped or because presenting is synced to the display.
+func (v *Engine) FramePeriod() float64 {
+	if !v.focused && v.idleFPS > 0.0 {
+		return 1000.0 / v.idleFPS
+	}
+
+	if v.frameMode == FrameModeCapped {
+		return 1000.0 / v.targetFPS
+	}
+
+	return 0.0
+}
+
+// onFocus is called when the window gains or loses focus.
+func (v *Engine) onFocus(focused bool) {
+	v.focused = focused
+}
@@ -1,24 +1,66 @@
 package engine
 
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// ErrSceneCycle is returned, or panicked by Add, when a node would become
+// its own ancestor.
+var ErrSceneCycle = errors.New("node can't be added to its own subtree")
+
 type IGroupNode interface {
 	INode
-	Add(n INode) // Last node added is render underneath
+	// Add appends n, removing it from any previous parent first. Among
+	// siblings with the same z-index the last node added renders on top.
+	Add(n INode)
+	// InsertAt inserts n before the child at index. An index past the
+	// end appends.
+	InsertAt(index int, n INode)
 	Remove(n INode)
+	// Find returns the index of n, or -1 and nil if n isn't a child.
 	Find(n INode) (f int, fno INode)
+	// Children returns the group's children in render order. Don't
+	// modify it.
+	Children() []INode
+
+	// BringToFront renders n on top of its siblings, raising its z-index
+	// if needed.
+	BringToFront(n INode)
+	// SendToBack renders n underneath its siblings, lowering its z-index
+	// if needed.
+	SendToBack(n INode)
+
+	// SetTimeScale scales dt for this group's subtree, on top of any
+	// ancestor's and the engine clock's scale.
+	SetTimeScale(scale float64)
+	TimeScale() float64
+
+	childOrderChanged()
 }
 
 // GroupNode is a collection of nodes
 type GroupNode struct {
 	BaseNode // is-a
 
+	// Children stably sorted by z-index, see sortChildren
 	nodes []INode
+	// A child was inserted or changed its z-index
+	orderDirty bool
+
+	timeScale float64
+
+	// Copy of nodes Update iterates so children can be added and
+	// removed by timers and actions
+	updating []INode
 }
 
 func NewGroupNode(parent IGroupNode, autoAdd bool) IGroupNode {
 	g := new(GroupNode)
 	g.Initialize()
-	g.parent = parent
 	g.nodes = []INode{}
+	g.timeScale = 1.0
 
 	if autoAdd {
 		parent.Add(g)
@@ -26,10 +68,46 @@ func NewGroupNode(parent IGroupNode, autoAdd bool) IGroupNode {
 	return g
 }
 
+// Add appends n to the group. If the group is running n and its subtree
+// enter.
 func (gn *GroupNode) Add(n INode) {
-	gn.nodes = append(gn.nodes, n)
+	gn.InsertAt(len(gn.nodes), n)
 }
 
+// InsertAt inserts n before the child at index. If n already has a
+// parent it is removed from it first.
+func (gn *GroupNode) InsertAt(index int, n INode) {
+	if IsAncestor(n, gn) {
+		panic(ErrSceneCycle)
+	}
+
+	if p := n.Parent(); p != nil {
+		p.Remove(n)
+	}
+
+	if index < 0 {
+		index = 0
+	}
+	if index > len(gn.nodes) {
+		index = len(gn.nodes)
+	}
+
+	gn.nodes = append(gn.nodes, nil)
+	copy(gn.nodes[index+1:], gn.nodes[index:])
+	gn.nodes[index] = n
+	gn.orderDirty = true
+	layersChanged()
+
+	n.setParent(gn)
+	n.OnAdded(gn)
+
+	if gn.running {
+		enterTree(n)
+	}
+}
+
+// Remove removes n from the group and clears its parent. If n is running
+// it and its subtree exit.
 func (gn *GroupNode) Remove(n INode) {
 	j, no := gn.Find(n)
 	if no != nil {
@@ -38,27 +116,119 @@ func (gn *GroupNode) Remove(n INode) {
 		copy(gn.nodes[j:], gn.nodes[j+1:])
 		gn.nodes[len(gn.nodes)-1] = nil // or the zero value of T
 		gn.nodes = gn.nodes[:len(gn.nodes)-1]
+
+		layersChanged()
+
+		no.setParent(nil)
+		exitTree(no)
 	}
 }
 
 func (gn *GroupNode) Find(n INode) (f int, fno INode) {
+	gn.sortChildren()
+
 	for i, no := range gn.nodes {
 		if no == n {
-			f = i
-			fno = no
-			break
+			return i, no
+		}
+	}
+	return -1, nil
+}
+
+func (gn *GroupNode) Children() []INode {
+	gn.sortChildren()
+	return gn.nodes
+}
+
+func (gn *GroupNode) BringToFront(n INode) {
+	j, no := gn.Find(n)
+	if no == nil {
+		return
+	}
+
+	copy(gn.nodes[j:], gn.nodes[j+1:])
+	gn.nodes[len(gn.nodes)-1] = n
+
+	z := n.ZIndex()
+	for _, sibling := range gn.nodes {
+		if sibling.ZIndex() > z {
+			z = sibling.ZIndex()
+		}
+	}
+	n.SetZIndex(z)
+}
+
+func (gn *GroupNode) SendToBack(n INode) {
+	j, no := gn.Find(n)
+	if no == nil {
+		return
+	}
+
+	copy(gn.nodes[1:j+1], gn.nodes[:j])
+	gn.nodes[0] = n
+
+	z := n.ZIndex()
+	for _, sibling := range gn.nodes {
+		if sibling.ZIndex() < z {
+			z = sibling.ZIndex()
 		}
 	}
-	return f, fno
+	n.SetZIndex(z)
+}
+
+func (gn *GroupNode) childOrderChanged() {
+	gn.orderDirty = true
+}
+
+// sortChildren restores z-index order. The sort is stable so siblings
+// with equal z-indices keep their relative order.
+func (gn *GroupNode) sortChildren() {
+	if !gn.orderDirty {
+		return
+	}
+
+	sort.SliceStable(gn.nodes, func(i, j int) bool {
+		return gn.nodes[i].ZIndex() < gn.nodes[j].ZIndex()
+	})
+	gn.orderDirty = false
+}
+
+func (gn *GroupNode) SetTimeScale(scale float64) {
+	if scale < 0.0 {
+		scale = 0.0
+	}
+	gn.timeScale = scale
+}
+
+func (gn *GroupNode) TimeScale() float64 {
+	return gn.timeScale
 }
 
 func (gn *GroupNode) Update(dt float64) {
+	dt *= gn.timeScale
+
 	// Update properties of the group node
 	gn.BaseNode.Update(dt)
+	gn.tickTimers(dt)
+
+	gn.updating = append(gn.updating[:0], gn.nodes...)
+
+	for i, n := range gn.updating {
+		gn.updating[i] = nil
+
+		// Skip children removed earlier in this update
+		if n.Parent() != IGroupNode(gn) {
+			continue
+		}
 
-	for _, n := range gn.nodes {
 		n.Update(dt)
+
+		// Groups tick their own timers with their time scale applied
+		if _, ok := n.(IGroupNode); !ok {
+			n.tickTimers(dt)
+		}
 	}
+	gn.updating = gn.updating[:0]
 
 	// Update node's transform if dirty
 }
@@ -69,10 +239,12 @@ func (gn *GroupNode) Render(context *RenderContext) {
 	}
 
 	// Save context state first
-	context.Save()
+	context.push()
 
 	// Append this node's transform onto the context and then render
-	context.Transform(gn.calcTransform())
+	context.enterNode(&gn.BaseNode)
+
+	gn.sortChildren()
 
 	for _, n := range gn.nodes {
 		// fmt.Printf("GroupNode render: %s\n", n)
@@ -80,7 +252,9 @@ func (gn *GroupNode) Render(context *RenderContext) {
 	}
 
 	// Now draw this node if it has an geometry, typically it doesn't
-	gn.Draw(context)
+	if context.ShouldDraw() {
+		gn.Draw(context)
+	}
 
 	context.Restore()
 }
@@ -92,3 +266,52 @@ func (gn *GroupNode) Draw(context *RenderContext) {
 	// context.DrawPolygon(0, 0, 1, 1, true, n.SolidColor)
 	// Draw using transformed geometry
 }
+
+// IsAncestor reports whether ancestor is node or one of node's ancestors.
+func IsAncestor(ancestor INode, node INode) bool {
+	for n := node; n != nil; {
+		if n == ancestor {
+			return true
+		}
+		p := n.Parent()
+		if p == nil {
+			break
+		}
+		n = p
+	}
+	return false
+}
+
+// Reparent moves n to newParent. If keepWorld is true n's position,
+// rotation and scale are adjusted so it stays where it is in world space.
+// Skew, which can arise from non-uniformly scaled ancestors, can't be
+// preserved.
+func Reparent(n INode, newParent IGroupNode, keepWorld bool) error {
+	if IsAncestor(n, newParent) {
+		return ErrSceneCycle
+	}
+
+	if keepWorld {
+		local := AffinePool.Pop()
+		AffineTransformMultiply(n.NodeToWorldTransform(), newParent.WorldToNodeTransform(), local)
+
+		// The anchor's pivot lands on the position
+		px := n.Anchor().X * n.ContentSize().X
+		py := n.Anchor().Y * n.ContentSize().Y
+		n.SetPositionBy2Comp(local.a*px+local.c*py+local.tx, local.b*px+local.d*py+local.ty)
+		n.SetRotation(math.Atan2(local.b, local.a))
+
+		sx := math.Hypot(local.a, local.b)
+		sy := math.Hypot(local.c, local.d)
+		if local.a*local.d-local.b*local.c < 0.0 {
+			sy = -sy
+		}
+		n.SetScale(NewVector3With2Components(sx, sy))
+
+		AffinePool.Push(local)
+	}
+
+	newParent.Add(n)
+
+	return nil
+}
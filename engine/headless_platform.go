@@ -0,0 +1,119 @@
+package engine
+
+import (
+	"image"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// HeadlessPlatform runs the engine without a window. Nothing is presented;
+// the drawing buffer is left for inspection. Input is synthetic and is
+// queued until the next frame pumps events.
+type HeadlessPlatform struct {
+	// Queued synthetic events, posted in order on the next PumpEvents
+	pending []IEvent
+
+	mx, my int32
+
+	frames int
+}
+
+// NewHeadlessPlatform creates a platform that never touches SDL's video
+// subsystem.
+func NewHeadlessPlatform() *HeadlessPlatform {
+	return new(HeadlessPlatform)
+}
+
+// Initialize does nothing as there are no native resources.
+func (p *HeadlessPlatform) Initialize(title string, width, height int32) error {
+	return nil
+}
+
+// SetVSync does nothing as nothing is presented.
+func (p *HeadlessPlatform) SetVSync(enabled bool) error {
+	return nil
+}
+
+// Configure does nothing as there is no overlay.
+func (p *HeadlessPlatform) Configure(font *Font) error {
+	return nil
+}
+
+// PumpEvents posts any queued synthetic events to the engine.
+func (p *HeadlessPlatform) PumpEvents(engine *Engine) {
+	pending := p.pending
+	p.pending = nil
+
+	for _, event := range pending {
+		engine.postEvent(event)
+	}
+}
+
+// Present only counts frames.
+func (p *HeadlessPlatform) Present(pixels *image.RGBA, stats *FrameStats) {
+	p.frames++
+}
+
+// Delay returns immediately.
+func (p *HeadlessPlatform) Delay(ms uint32) {
+}
+
+// Close does nothing.
+func (p *HeadlessPlatform) Close() {
+}
+
+// Frames returns how many frames have been presented.
+func (p *HeadlessPlatform) Frames() int {
+	return p.frames
+}
+
+// Inject queues any event for the next frame.
+func (p *HeadlessPlatform) Inject(event IEvent) {
+	p.pending = append(p.pending, event)
+}
+
+// PressKey queues a key down.
+func (p *HeadlessPlatform) PressKey(scancode sdl.Scancode) {
+	p.Inject(NewKeyEvent(EventKeyDown, scancode, ModNone))
+}
+
+// ReleaseKey queues a key up.
+func (p *HeadlessPlatform) ReleaseKey(scancode sdl.Scancode) {
+	p.Inject(NewKeyEvent(EventKeyUp, scancode, ModNone))
+}
+
+// MoveMouse queues a mouse motion to x,y.
+func (p *HeadlessPlatform) MoveMouse(x, y int32) {
+	p.Inject(NewMouseMotionEvent(x, y, x-p.mx, y-p.my))
+	p.mx = x
+	p.my = y
+}
+
+// PressButton queues a mouse button down at the last mouse position.
+func (p *HeadlessPlatform) PressButton(button MouseButton) {
+	p.Inject(NewMouseButtonEvent(EventMouseButtonDown, button, p.mx, p.my))
+}
+
+// ReleaseButton queues a mouse button up at the last mouse position.
+func (p *HeadlessPlatform) ReleaseButton(button MouseButton) {
+	p.Inject(NewMouseButtonEvent(EventMouseButtonUp, button, p.mx, p.my))
+}
+
+// TypeText queues text input.
+func (p *HeadlessPlatform) TypeText(text string) {
+	p.Inject(NewTextInputEvent(text))
+}
+
+// SetFocus queues a window focus change.
+func (p *HeadlessPlatform) SetFocus(focused bool) {
+	if focused {
+		p.Inject(NewWindowEvent(WindowFocusGained))
+	} else {
+		p.Inject(NewWindowEvent(WindowFocusLost))
+	}
+}
+
+// RequestQuit queues a quit request, as if the window was closed.
+func (p *HeadlessPlatform) RequestQuit() {
+	p.Inject(NewQuitEvent())
+}
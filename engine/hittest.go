@@ -0,0 +1,73 @@
+package engine
+
+// HitTest returns the top-most visible node containing the point x,y, or
+// nil. The point is in the space root is rendered into, typically window
+// coordinates. Nodes are tested in reverse render order, highest layer
+// first, by inverting each node's accumulated transform and asking the
+// node if the local point is inside it.
+func HitTest(root INode, x, y float64) INode {
+	var parent AffineTransform
+	parent.ToIdentity()
+
+	point := VectorsPool.Pop()
+	point.Set2Components(x, y)
+
+	var hit INode
+
+	layers := layersOf(root)
+	if len(layers) <= 1 {
+		hit = hitTest(root, &parent, point, LayerWorld, nil)
+	} else {
+		for i := len(layers) - 1; i >= 0 && hit == nil; i-- {
+			hit = hitTest(root, &parent, point, LayerWorld, &layers[i])
+		}
+	}
+
+	VectorsPool.Push(point)
+
+	return hit
+}
+
+// hitTest tests n's subtree. If layer isn't nil only nodes in that layer
+// can be hit. Transforms live on the stack rather than in AffinePool so
+// the depth of the tree isn't limited by the pool's size.
+func hitTest(n INode, parent *AffineTransform, point *Vector3, inherited RenderLayer, layer *RenderLayer) INode {
+	if !n.IsVisible() {
+		return nil
+	}
+
+	// Same concatenation order the RenderContext uses
+	var world AffineTransform
+	AffineTransformMultiply(n.calcTransform(), parent, &world)
+
+	var hit INode
+	nodeLayer := effectiveLayer(n, inherited)
+
+	// A group draws itself after its children so it is tested first,
+	// then its children from last (top) to first.
+	if (layer == nil || *layer == nodeLayer) && pointInsideWorld(n, &world, point) {
+		hit = n
+	} else if g, ok := n.(IGroupNode); ok {
+		children := g.Children()
+		for i := len(children) - 1; i >= 0 && hit == nil; i-- {
+			hit = hitTest(children[i], &world, point, nodeLayer, layer)
+		}
+	}
+
+	return hit
+}
+
+// pointInsideWorld maps a world point into n's local space and tests it.
+func pointInsideWorld(n INode, world *AffineTransform, point *Vector3) bool {
+	var inverse AffineTransform
+	AffineTransformInvertTo(world, &inverse)
+
+	local := VectorsPool.Pop()
+	inverse.ApplyTo(point, local)
+
+	inside := n.PointInside(local)
+
+	VectorsPool.Push(local)
+
+	return inside
+}
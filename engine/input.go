@@ -0,0 +1,295 @@
+package engine
+
+import (
+	"sort"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+const (
+	// PriorityEngine is the priority of the engine's own listener, for
+	// example, ESC to quit. Any listener can consume events before it.
+	PriorityEngine = -1000000
+	// PriorityDefault is a typical listener priority.
+	PriorityDefault = 0
+)
+
+// InputListener receives input events. Consume the event to stop it
+// propagating to lower priority listeners.
+type InputListener func(event IEvent)
+
+// ListenerID identifies a registered listener for removal.
+type ListenerID int
+
+type listenerEntry struct {
+	id       ListenerID
+	priority int
+	listener InputListener
+}
+
+// Input dispatches typed events to listeners, highest priority first, and
+// tracks device state so it can be queried, including whether a key or
+// button was pressed or released since the last simulation step.
+type Input struct {
+	listeners []*listenerEntry
+	nextID    ListenerID
+
+	keys         []bool
+	keysPressed  []bool
+	keysReleased []bool
+	// Scancodes whose edge flags need clearing
+	keysChanged []sdl.Scancode
+
+	buttons         [maxMouseButtons]bool
+	buttonsPressed  [maxMouseButtons]bool
+	buttonsReleased [maxMouseButtons]bool
+
+	mx, my         int32
+	dx, dy         int32
+	wheelX, wheelY int32
+
+	text string
+}
+
+// NewInput creates an Input with no listeners.
+func NewInput() *Input {
+	i := new(Input)
+	i.keys = make([]bool, sdl.NUM_SCANCODES)
+	i.keysPressed = make([]bool, sdl.NUM_SCANCODES)
+	i.keysReleased = make([]bool, sdl.NUM_SCANCODES)
+	return i
+}
+
+// AddListener registers a listener. Listeners with a higher priority are
+// called first; equal priorities are called in registration order.
+func (i *Input) AddListener(priority int, listener InputListener) ListenerID {
+	i.nextID++
+
+	i.listeners = append(i.listeners, &listenerEntry{i.nextID, priority, listener})
+
+	sort.SliceStable(i.listeners, func(a, b int) bool {
+		return i.listeners[a].priority > i.listeners[b].priority
+	})
+
+	return i.nextID
+}
+
+// RemoveListener unregisters a listener. It is safe to call from within a
+// listener.
+func (i *Input) RemoveListener(id ListenerID) {
+	for j, l := range i.listeners {
+		if l.id == id {
+			// Copy rather than shift in place so an in progress
+			// dispatch isn't disturbed.
+			listeners := make([]*listenerEntry, 0, len(i.listeners)-1)
+			listeners = append(listeners, i.listeners[:j]...)
+			i.listeners = append(listeners, i.listeners[j+1:]...)
+			return
+		}
+	}
+}
+
+// Dispatch records the event in the device state and then passes it to
+// each listener until one consumes it. State is recorded even if the
+// event is consumed so queries always reflect the devices.
+func (i *Input) Dispatch(event IEvent) {
+	i.record(event)
+
+	for _, l := range i.listeners {
+		l.listener(event)
+		if event.IsConsumed() {
+			break
+		}
+	}
+}
+
+func (i *Input) record(event IEvent) {
+	switch t := event.(type) {
+	case *KeyEvent:
+		if int(t.Scancode) >= len(i.keys) {
+			return
+		}
+		switch t.Type() {
+		case EventKeyDown:
+			if !i.keys[t.Scancode] {
+				i.keysPressed[t.Scancode] = true
+				i.keysChanged = append(i.keysChanged, t.Scancode)
+			}
+			i.keys[t.Scancode] = true
+		case EventKeyUp:
+			if i.keys[t.Scancode] {
+				i.keysReleased[t.Scancode] = true
+				i.keysChanged = append(i.keysChanged, t.Scancode)
+			}
+			i.keys[t.Scancode] = false
+		}
+	case *MouseButtonEvent:
+		i.mx = t.X
+		i.my = t.Y
+		if int(t.Button) >= maxMouseButtons {
+			return
+		}
+		switch t.Type() {
+		case EventMouseButtonDown:
+			if !i.buttons[t.Button] {
+				i.buttonsPressed[t.Button] = true
+			}
+			i.buttons[t.Button] = true
+		case EventMouseButtonUp:
+			if i.buttons[t.Button] {
+				i.buttonsReleased[t.Button] = true
+			}
+			i.buttons[t.Button] = false
+		}
+	case *MouseMotionEvent:
+		i.mx = t.X
+		i.my = t.Y
+		i.dx += t.XRel
+		i.dy += t.YRel
+	case *MouseWheelEvent:
+		i.wheelX += t.X
+		i.wheelY += t.Y
+	case *TextInputEvent:
+		i.text += t.Text
+	case *WindowEvent:
+		if t.Kind == WindowFocusLost {
+			i.releaseAll()
+		}
+	}
+}
+
+// releaseAll drops held keys and buttons, for example, when focus is lost
+// and the matching up events will never arrive.
+func (i *Input) releaseAll() {
+	for sc, down := range i.keys {
+		if down {
+			i.keys[sc] = false
+			i.keysReleased[sc] = true
+			i.keysChanged = append(i.keysChanged, sdl.Scancode(sc))
+		}
+	}
+	for b, down := range i.buttons {
+		if down {
+			i.buttons[b] = false
+			i.buttonsReleased[b] = true
+		}
+	}
+}
+
+// heldKeys returns the scancodes of the keys being held.
+func (i *Input) heldKeys() []sdl.Scancode {
+	var held []sdl.Scancode
+	for sc, down := range i.keys {
+		if down {
+			held = append(held, sdl.Scancode(sc))
+		}
+	}
+	return held
+}
+
+// restoreHeld replaces the device state, without any edges, for example,
+// as a replay starts.
+func (i *Input) restoreHeld(keys []sdl.Scancode, buttons [maxMouseButtons]bool, x, y int32) {
+	for sc := range i.keys {
+		i.keys[sc] = false
+	}
+	for _, sc := range keys {
+		if int(sc) < len(i.keys) {
+			i.keys[sc] = true
+		}
+	}
+	i.buttons = buttons
+	i.mx = x
+	i.my = y
+
+	i.endStep()
+}
+
+// endStep clears the per step edges and accumulators. The engine calls it
+// after each simulation step.
+func (i *Input) endStep() {
+	for _, sc := range i.keysChanged {
+		i.keysPressed[sc] = false
+		i.keysReleased[sc] = false
+	}
+	i.keysChanged = i.keysChanged[:0]
+
+	for b := range i.buttonsPressed {
+		i.buttonsPressed[b] = false
+		i.buttonsReleased[b] = false
+	}
+
+	i.dx = 0
+	i.dy = 0
+	i.wheelX = 0
+	i.wheelY = 0
+	i.text = ""
+}
+
+// IsKeyDown is true while the key is held.
+func (i *Input) IsKeyDown(scancode sdl.Scancode) bool {
+	return int(scancode) < len(i.keys) && i.keys[scancode]
+}
+
+// IsKeyPressed is true if the key went down since the last step.
+func (i *Input) IsKeyPressed(scancode sdl.Scancode) bool {
+	return int(scancode) < len(i.keys) && i.keysPressed[scancode]
+}
+
+// IsKeyReleased is true if the key went up since the last step.
+func (i *Input) IsKeyReleased(scancode sdl.Scancode) bool {
+	return int(scancode) < len(i.keys) && i.keysReleased[scancode]
+}
+
+// Modifiers returns the modifier keys currently held.
+func (i *Input) Modifiers() KeyModifier {
+	m := ModNone
+	if i.keys[sdl.SCANCODE_LSHIFT] || i.keys[sdl.SCANCODE_RSHIFT] {
+		m |= ModShift
+	}
+	if i.keys[sdl.SCANCODE_LCTRL] || i.keys[sdl.SCANCODE_RCTRL] {
+		m |= ModCtrl
+	}
+	if i.keys[sdl.SCANCODE_LALT] || i.keys[sdl.SCANCODE_RALT] {
+		m |= ModAlt
+	}
+	if i.keys[sdl.SCANCODE_LGUI] || i.keys[sdl.SCANCODE_RGUI] {
+		m |= ModGUI
+	}
+	return m
+}
+
+// IsButtonDown is true while the mouse button is held.
+func (i *Input) IsButtonDown(button MouseButton) bool {
+	return int(button) < maxMouseButtons && i.buttons[button]
+}
+
+// IsButtonPressed is true if the mouse button went down since the last step.
+func (i *Input) IsButtonPressed(button MouseButton) bool {
+	return int(button) < maxMouseButtons && i.buttonsPressed[button]
+}
+
+// IsButtonReleased is true if the mouse button went up since the last step.
+func (i *Input) IsButtonReleased(button MouseButton) bool {
+	return int(button) < maxMouseButtons && i.buttonsReleased[button]
+}
+
+// MousePosition returns the last known mouse position.
+func (i *Input) MousePosition() (x, y int32) {
+	return i.mx, i.my
+}
+
+// MouseDelta returns how far the mouse moved since the last step.
+func (i *Input) MouseDelta() (dx, dy int32) {
+	return i.dx, i.dy
+}
+
+// WheelDelta returns how far the wheel scrolled since the last step.
+func (i *Input) WheelDelta() (x, y int32) {
+	return i.wheelX, i.wheelY
+}
+
+// Text returns any text typed since the last step.
+func (i *Input) Text() string {
+	return i.text
+}
@@ -0,0 +1,232 @@
+package engine
+
+import (
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// EventType identifies the kind of an input event.
+type EventType int
+
+const (
+	EventKeyDown EventType = iota
+	EventKeyUp
+	// EventKeyRepeat is a key held down long enough to auto repeat.
+	EventKeyRepeat
+	EventMouseButtonDown
+	EventMouseButtonUp
+	EventMouseMotion
+	EventMouseWheel
+	EventTextInput
+	EventWindow
+	EventQuit
+)
+
+// KeyModifier is a bit set of held modifier keys.
+type KeyModifier uint16
+
+const (
+	ModShift KeyModifier = 1 << iota
+	ModCtrl
+	ModAlt
+	ModGUI
+
+	ModNone KeyModifier = 0
+)
+
+// MouseButton identifies a mouse button. The values match SDL's.
+type MouseButton uint8
+
+const (
+	MouseButtonLeft   MouseButton = sdl.BUTTON_LEFT
+	MouseButtonMiddle MouseButton = sdl.BUTTON_MIDDLE
+	MouseButtonRight  MouseButton = sdl.BUTTON_RIGHT
+	MouseButtonX1     MouseButton = 4
+	MouseButtonX2     MouseButton = 5
+
+	maxMouseButtons = 6
+)
+
+// WindowEventKind identifies what happened to the window.
+type WindowEventKind int
+
+const (
+	WindowShown WindowEventKind = iota
+	WindowHidden
+	WindowResized
+	WindowMinimized
+	WindowRestored
+	WindowMouseEnter
+	WindowMouseLeave
+	WindowFocusGained
+	WindowFocusLost
+	WindowClose
+)
+
+// IEvent is implemented by every input event. A listener consumes an
+// event to stop it propagating to lower priority listeners.
+type IEvent interface {
+	Type() EventType
+	Consume()
+	IsConsumed() bool
+}
+
+type baseEvent struct {
+	eventType EventType
+	consumed  bool
+}
+
+// Type returns the kind of event.
+func (e *baseEvent) Type() EventType {
+	return e.eventType
+}
+
+// Consume stops the event propagating to any further listeners.
+func (e *baseEvent) Consume() {
+	e.consumed = true
+}
+
+// reset clears the consumed flag so the event can be dispatched again.
+func (e *baseEvent) reset() {
+	e.consumed = false
+}
+
+// IsConsumed is true once a listener has consumed the event.
+func (e *baseEvent) IsConsumed() bool {
+	return e.consumed
+}
+
+// KeyEvent is a key down, up or repeat.
+type KeyEvent struct {
+	baseEvent
+
+	Scancode  sdl.Scancode
+	Keycode   sdl.Keycode
+	Modifiers KeyModifier
+}
+
+// NewKeyEvent creates a key event of type EventKeyDown, EventKeyUp or
+// EventKeyRepeat.
+func NewKeyEvent(eventType EventType, scancode sdl.Scancode, modifiers KeyModifier) *KeyEvent {
+	e := new(KeyEvent)
+	e.eventType = eventType
+	e.Scancode = scancode
+	e.Modifiers = modifiers
+	return e
+}
+
+// MouseButtonEvent is a mouse button down or up at X,Y.
+type MouseButtonEvent struct {
+	baseEvent
+
+	Button MouseButton
+	X, Y   int32
+	Clicks uint8
+}
+
+// NewMouseButtonEvent creates an EventMouseButtonDown or EventMouseButtonUp.
+func NewMouseButtonEvent(eventType EventType, button MouseButton, x, y int32) *MouseButtonEvent {
+	e := new(MouseButtonEvent)
+	e.eventType = eventType
+	e.Button = button
+	e.X = x
+	e.Y = y
+	e.Clicks = 1
+	return e
+}
+
+// MouseMotionEvent is the mouse moving to X,Y by XRel,YRel.
+type MouseMotionEvent struct {
+	baseEvent
+
+	X, Y       int32
+	XRel, YRel int32
+}
+
+// NewMouseMotionEvent creates an EventMouseMotion.
+func NewMouseMotionEvent(x, y, xRel, yRel int32) *MouseMotionEvent {
+	e := new(MouseMotionEvent)
+	e.eventType = EventMouseMotion
+	e.X = x
+	e.Y = y
+	e.XRel = xRel
+	e.YRel = yRel
+	return e
+}
+
+// MouseWheelEvent is a scroll by X,Y. +Y is away from the user.
+type MouseWheelEvent struct {
+	baseEvent
+
+	X, Y int32
+}
+
+// NewMouseWheelEvent creates an EventMouseWheel.
+func NewMouseWheelEvent(x, y int32) *MouseWheelEvent {
+	e := new(MouseWheelEvent)
+	e.eventType = EventMouseWheel
+	e.X = x
+	e.Y = y
+	return e
+}
+
+// TextInputEvent carries UTF-8 text typed by the user.
+type TextInputEvent struct {
+	baseEvent
+
+	Text string
+}
+
+// NewTextInputEvent creates an EventTextInput.
+func NewTextInputEvent(text string) *TextInputEvent {
+	e := new(TextInputEvent)
+	e.eventType = EventTextInput
+	e.Text = text
+	return e
+}
+
+// WindowEvent is a change to the window, for example, a focus change.
+type WindowEvent struct {
+	baseEvent
+
+	Kind WindowEventKind
+	// Data1 and Data2 are the new width and height for WindowResized
+	Data1, Data2 int32
+}
+
+// NewWindowEvent creates an EventWindow.
+func NewWindowEvent(kind WindowEventKind) *WindowEvent {
+	e := new(WindowEvent)
+	e.eventType = EventWindow
+	e.Kind = kind
+	return e
+}
+
+// QuitEvent is a request to close the application.
+type QuitEvent struct {
+	baseEvent
+}
+
+// NewQuitEvent creates an EventQuit.
+func NewQuitEvent() *QuitEvent {
+	e := new(QuitEvent)
+	e.eventType = EventQuit
+	return e
+}
+
+// modifiersFromSDL converts SDL's left/right modifier bits.
+func modifiersFromSDL(mod uint16) KeyModifier {
+	m := ModNone
+	if mod&sdl.KMOD_SHIFT != 0 {
+		m |= ModShift
+	}
+	if mod&sdl.KMOD_CTRL != 0 {
+		m |= ModCtrl
+	}
+	if mod&sdl.KMOD_ALT != 0 {
+		m |= ModAlt
+	}
+	if mod&sdl.KMOD_GUI != 0 {
+		m |= ModGUI
+	}
+	return m
+}
@@ -0,0 +1,358 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// ActionKind is either a digital (on/off) or an axis (-1..1) action.
+type ActionKind int
+
+const (
+	ActionDigital ActionKind = iota
+	ActionAxis
+)
+
+// BindingDevice selects the device a binding listens to.
+type BindingDevice string
+
+const (
+	DeviceKey         BindingDevice = "key"
+	DeviceMouseButton BindingDevice = "mouse"
+)
+
+// Binding maps a key or mouse button, optionally combined with modifier
+// keys, onto an action.
+type Binding struct {
+	Device BindingDevice `json:"device"`
+	// Code is a scancode for DeviceKey or a MouseButton for DeviceMouseButton
+	Code int `json:"code"`
+	// Modifiers that must also be held. Bindings without modifiers ignore
+	// the modifier state.
+	Modifiers KeyModifier `json:"modifiers,omitempty"`
+	// Scale is the value contributed to an axis action while held, for
+	// example, -1 for "left" and +1 for "right". Unused by digital actions.
+	Scale float64 `json:"scale,omitempty"`
+}
+
+// NewKeyBinding binds a key, and optionally modifiers, to a digital action.
+func NewKeyBinding(scancode sdl.Scancode, modifiers KeyModifier) Binding {
+	return Binding{Device: DeviceKey, Code: int(scancode), Modifiers: modifiers}
+}
+
+// NewMouseBinding binds a mouse button to a digital action.
+func NewMouseBinding(button MouseButton) Binding {
+	return Binding{Device: DeviceMouseButton, Code: int(button)}
+}
+
+// NewAxisKeyBinding binds a key to an axis action with the given scale.
+func NewAxisKeyBinding(scancode sdl.Scancode, scale float64) Binding {
+	return Binding{Device: DeviceKey, Code: int(scancode), Scale: scale}
+}
+
+// InputAction is a named action and the bindings that trigger it.
+type InputAction struct {
+	Name     string     `json:"name"`
+	Kind     ActionKind `json:"kind"`
+	Bindings []Binding  `json:"bindings"`
+}
+
+// InputMap layers named actions, such as "fire" or "move_x", over Input so
+// games don't query devices directly and controls can be rebound.
+type InputMap struct {
+	input   *Input
+	actions map[string]*InputAction
+}
+
+type inputMapFile struct {
+	Actions []*InputAction `json:"actions"`
+}
+
+// NewInputMap creates an empty map over input.
+func NewInputMap(input *Input) *InputMap {
+	m := new(InputMap)
+	m.input = input
+	m.actions = make(map[string]*InputAction)
+	return m
+}
+
+// AddAction defines an action. Redefining an action replaces its kind and
+// keeps its bindings.
+func (m *InputMap) AddAction(name string, kind ActionKind) *InputAction {
+	a, ok := m.actions[name]
+	if !ok {
+		a = &InputAction{Name: name}
+		m.actions[name] = a
+	}
+	a.Kind = kind
+	return a
+}
+
+// Action returns the named action or nil.
+func (m *InputMap) Action(name string) *InputAction {
+	return m.actions[name]
+}
+
+// Bind adds a binding to a defined action.
+func (m *InputMap) Bind(name string, binding Binding) error {
+	a, ok := m.actions[name]
+	if !ok {
+		return fmt.Errorf("unknown action '%s'", name)
+	}
+
+	a.Bindings = append(a.Bindings, binding)
+	return nil
+}
+
+// Unbind removes all of an action's bindings.
+func (m *InputMap) Unbind(name string) {
+	if a, ok := m.actions[name]; ok {
+		a.Bindings = nil
+	}
+}
+
+// IsActive is true while any of the action's bindings is held.
+func (m *InputMap) IsActive(name string) bool {
+	return m.any(name, m.isHeld)
+}
+
+// IsPressed is true if any binding was pressed since the last step.
+func (m *InputMap) IsPressed(name string) bool {
+	return m.any(name, m.isPressed)
+}
+
+// IsReleased is true if any binding was released since the last step.
+func (m *InputMap) IsReleased(name string) bool {
+	return m.any(name, m.isReleased)
+}
+
+// Axis sums the scales of every held binding, clamped to -1..1
+func (m *InputMap) Axis(name string) float64 {
+	a, ok := m.actions[name]
+	if !ok {
+		return 0.0
+	}
+
+	value := 0.0
+	for _, b := range a.Bindings {
+		if m.isHeld(b) {
+			value += b.Scale
+		}
+	}
+
+	if value < -1.0 {
+		value = -1.0
+	} else if value > 1.0 {
+		value = 1.0
+	}
+
+	return value
+}
+
+func (m *InputMap) any(name string, test func(b Binding) bool) bool {
+	a, ok := m.actions[name]
+	if !ok {
+		return false
+	}
+
+	for _, b := range a.Bindings {
+		if test(b) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *InputMap) modifiersHeld(b Binding) bool {
+	return m.input.Modifiers()&b.Modifiers == b.Modifiers
+}
+
+func (m *InputMap) isHeld(b Binding) bool {
+	switch b.Device {
+	case DeviceKey:
+		return m.input.IsKeyDown(sdl.Scancode(b.Code)) && m.modifiersHeld(b)
+	case DeviceMouseButton:
+		return m.input.IsButtonDown(MouseButton(b.Code)) && m.modifiersHeld(b)
+	}
+	return false
+}
+
+func (m *InputMap) isPressed(b Binding) bool {
+	switch b.Device {
+	case DeviceKey:
+		return m.input.IsKeyPressed(sdl.Scancode(b.Code)) && m.modifiersHeld(b)
+	case DeviceMouseButton:
+		return m.input.IsButtonPressed(MouseButton(b.Code)) && m.modifiersHeld(b)
+	}
+	return false
+}
+
+func (m *InputMap) isReleased(b Binding) bool {
+	switch b.Device {
+	case DeviceKey:
+		return m.input.IsKeyReleased(sdl.Scancode(b.Code))
+	case DeviceMouseButton:
+		return m.input.IsButtonReleased(MouseButton(b.Code))
+	}
+	return false
+}
+
+// Save writes every action and its bindings to a JSON file.
+func (m *InputMap) Save(path string) error {
+	names := make([]string, 0, len(m.actions))
+	for name := range m.actions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	file := inputMapFile{}
+	for _, name := range names {
+		file.Actions = append(file.Actions, m.actions[name])
+	}
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
+
+// Load reads actions from a JSON file written by Save. Actions in the file
+// replace any existing definition of the same name; others are untouched.
+// If any action or binding is invalid an error is returned and nothing is
+// changed.
+func (m *InputMap) Load(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	file := inputMapFile{}
+	err = json.Unmarshal(data, &file)
+	if err != nil {
+		return err
+	}
+
+	// Check everything first so a bad file changes nothing
+	for i, a := range file.Actions {
+		if a == nil {
+			return fmt.Errorf("input map action %d is null", i)
+		}
+		err = a.validate()
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, a := range file.Actions {
+		m.actions[a.Name] = a
+	}
+
+	return nil
+}
+
+func (a *InputAction) validate() error {
+	if a.Name == "" {
+		return fmt.Errorf("input map action has no name")
+	}
+	if a.Kind != ActionDigital && a.Kind != ActionAxis {
+		return fmt.Errorf("action '%s' has unknown kind %d", a.Name, a.Kind)
+	}
+
+	for _, b := range a.Bindings {
+		err := b.validate()
+		if err != nil {
+			return fmt.Errorf("action '%s': %v", a.Name, err)
+		}
+	}
+	return nil
+}
+
+func (b Binding) validate() error {
+	switch b.Device {
+	case DeviceKey:
+		if b.Code < 0 || b.Code >= int(sdl.NUM_SCANCODES) {
+			return fmt.Errorf("invalid scancode %d", b.Code)
+		}
+	case DeviceMouseButton:
+		if b.Code < 0 || b.Code >= maxMouseButtons {
+			return fmt.Errorf("invalid mouse button %d", b.Code)
+		}
+	default:
+		return fmt.Errorf("unknown binding device '%s'", b.Device)
+	}
+	return nil
+}
+
+// MarshalText writes the kind as "digital" or "axis".
+func (k ActionKind) MarshalText() ([]byte, error) {
+	switch k {
+	case ActionDigital:
+		return []byte("digital"), nil
+	case ActionAxis:
+		return []byte("axis"), nil
+	}
+	return nil, fmt.Errorf("unknown action kind %d", k)
+}
+
+// UnmarshalText reads "digital" or "axis".
+func (k *ActionKind) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "digital":
+		*k = ActionDigital
+	case "axis":
+		*k = ActionAxis
+	default:
+		return fmt.Errorf("unknown action kind '%s'", text)
+	}
+	return nil
+}
+
+var modifierNames = []struct {
+	mod  KeyModifier
+	name string
+}{
+	{ModShift, "shift"},
+	{ModCtrl, "ctrl"},
+	{ModAlt, "alt"},
+	{ModGUI, "gui"},
+}
+
+// MarshalText writes modifiers as, for example, "ctrl+shift".
+func (km KeyModifier) MarshalText() ([]byte, error) {
+	names := []string{}
+	for _, mn := range modifierNames {
+		if km&mn.mod != 0 {
+			names = append(names, mn.name)
+		}
+	}
+	return []byte(strings.Join(names, "+")), nil
+}
+
+// UnmarshalText reads modifiers written by MarshalText.
+func (km *KeyModifier) UnmarshalText(text []byte) error {
+	*km = ModNone
+	if len(text) == 0 {
+		return nil
+	}
+
+	for _, name := range strings.Split(string(text), "+") {
+		found := false
+		for _, mn := range modifierNames {
+			if strings.TrimSpace(name) == mn.name {
+				*km |= mn.mod
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("unknown modifier '%s'", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package engine
+
+// A node is running while it is part of the running scene, or of the
+// engine's root when no scene is running. enterTree and exitTree walk a
+// subtree as it joins or leaves, calling each node's OnEnter or OnExit.
+
+// enterTree marks n and its subtree as running. Parents enter before
+// their children. Nodes already running, for example, added by a parent's
+// OnEnter, are skipped.
+func enterTree(n INode) {
+	if n.IsRunning() {
+		return
+	}
+
+	n.setRunning(true)
+	n.OnEnter()
+
+	if g, ok := n.(IGroupNode); ok {
+		for _, child := range snapshot(g.Children()) {
+			enterTree(child)
+		}
+	}
+}
+
+// exitTree marks n and its subtree as no longer running. Children exit
+// before their parents.
+func exitTree(n INode) {
+	if !n.IsRunning() {
+		return
+	}
+
+	if g, ok := n.(IGroupNode); ok {
+		for _, child := range snapshot(g.Children()) {
+			exitTree(child)
+		}
+	}
+
+	n.setRunning(false)
+	n.OnExit()
+
+	// Nothing scheduled outlives the node's time in the scene
+	n.UnscheduleAll()
+}
+
+// snapshot copies children so callbacks can add or remove nodes safely.
+func snapshot(children []INode) []INode {
+	if len(children) == 0 {
+		return nil
+	}
+	return append([]INode(nil), children...)
+}
@@ -3,6 +3,7 @@ package engine
 import (
 	"fmt"
 	"image/color"
+	"math"
 )
 
 type Drawer func(*RenderContext)
@@ -24,33 +25,129 @@ type INode interface {
 	SetRotation(float64)
 	SetRotationByDegree(float64)
 
+	// Anchor is the pivot the node rotates and scales about and that is
+	// placed at its position. It is normalized to the content size, so
+	// 0.5,0.5 is the center; the default is the origin.
+	Anchor() *Vector3
+	SetAnchor(x, y float64)
+	// ContentSize is the size of the node's geometry in local units.
+	ContentSize() *Vector3
+	SetContentSize(width, height float64)
+
 	SetInvisible()
 	SetVisible()
 	IsVisible() bool
 
 	SetColor(color.RGBA)
+	Color() color.RGBA
+	// Opacity, 0 to 1, multiplies the opacity of the node's subtree.
+	Opacity() float64
+	SetOpacity(float64)
+	// Tint multiplies the colors of the node's subtree. White, the
+	// default, leaves them unchanged.
+	Tint() color.RGBA
+	SetTint(color.RGBA)
 	Name() string
 	SetName(string)
 
+	// Parent returns the group the node was added to or nil.
+	Parent() IGroupNode
+
+	// Tags label nodes for lookup, see FindByTag and Query.
+	AddTag(tag string)
+	RemoveTag(tag string)
+	HasTag(tag string) bool
+	Tags() []string
+
+	// ZIndex orders the node among its siblings. Lower values render
+	// first, underneath; equal values keep the order they were added in.
+	ZIndex() int
+	SetZIndex(z int)
+	// Layer is the render layer the node and, unless they set their own,
+	// its descendants draw in.
+	Layer() RenderLayer
+	SetLayer(layer RenderLayer)
+
+	// PointInside reports whether a point in the node's local space is
+	// inside the node. It is used for hit testing.
+	PointInside(local *Vector3) bool
+	SetPointerHandler(PointerHandler)
+	PointerHandler() PointerHandler
+
+	// NodeToWorldTransform returns the cached transform from this node's
+	// local space to world space. Don't modify it.
+	NodeToWorldTransform() *AffineTransform
+	// WorldToNodeTransform returns the cached inverse of NodeToWorldTransform.
+	WorldToNodeTransform() *AffineTransform
+	NodeToWorld(local *Vector3, out *Vector3)
+	WorldToNode(world *Vector3, out *Vector3)
+	// NodeToNode converts a point in this node's space into target's space.
+	NodeToNode(local *Vector3, target INode, out *Vector3)
+
+	// OnEnter is called when the node joins the running scene, either
+	// because its scene started running or it was added to a running
+	// parent. Register timers, listeners and the like here.
+	OnEnter()
+	// OnExit is called when the node leaves the running scene. Release
+	// anything registered in OnEnter here.
+	OnExit()
+	// OnAdded is called whenever the node is added to a parent, running
+	// or not.
+	OnAdded(parent IGroupNode)
+	// IsRunning is true between OnEnter and OnExit.
+	IsRunning() bool
+
+	// Timers, see scheduler.go
+	Schedule(callback TimerCallback) *Timer
+	ScheduleOnce(delay float64, callback func()) *Timer
+	ScheduleInterval(interval float64, repeats int, callback TimerCallback) *Timer
+	UnscheduleAll()
+
 	calcTransform() *AffineTransform
+	worldTransform() (*AffineTransform, uint64)
+	layerCache() *layerCache
+	setRunning(running bool)
+	setParent(parent IGroupNode)
+	tickTimers(dt float64)
 
 	String() string
 }
 
 type BaseNode struct {
 	name      string
+	tags      []string
 	parent    IGroupNode
 	transform *AffineTransform
 	dirty     bool
 	visible   bool
+	running   bool
 
 	position *Vector3
 	scale    *Vector3
 	rotation float64
 
+	anchor      *Vector3
+	contentSize *Vector3
+
+	zIndex int
+	layer  RenderLayer
+
 	SolidColor color.RGBA
+	opacity    float64
+	tint       color.RGBA
 
 	drawer Drawer
+
+	pointerHandler PointerHandler
+
+	timers []*Timer
+
+	// Bumped whenever the local transform changes
+	localVersion uint64
+	// World transform cache, see world.go
+	world *worldCache
+	// Layers of the subtree, see render_layers.go
+	layers layerCache
 }
 
 func (n *BaseNode) Initialize() {
@@ -61,39 +158,73 @@ func (n *BaseNode) Initialize() {
 	n.scale = NewVector3()
 	n.scale.Set2Components(1.0, 1.0)
 
+	n.anchor = NewVector3()
+	n.contentSize = NewVector3()
+
 	n.SolidColor = color.RGBA{255, 255, 255, 255}
+	n.opacity = 1.0
+	n.tint = color.RGBA{255, 255, 255, 255}
+	n.layer = LayerInherit
 	n.transform = NewAffineTransform()
+	n.world = newWorldCache()
+}
+
+// markDirty flags the local transform for recalculation which in turn
+// invalidates any cached world transforms of this node and its subtree.
+func (n *BaseNode) markDirty() {
+	n.dirty = true
+	n.localVersion++
 }
 
 func (n *BaseNode) SetColor(color color.RGBA) {
 	n.SolidColor = color
 }
 
+func (n *BaseNode) Color() color.RGBA {
+	return n.SolidColor
+}
+
+func (n *BaseNode) Opacity() float64 {
+	return n.opacity
+}
+
+func (n *BaseNode) SetOpacity(opacity float64) {
+	n.opacity = math.Max(0.0, math.Min(opacity, 1.0))
+}
+
+func (n *BaseNode) Tint() color.RGBA {
+	return n.tint
+}
+
+func (n *BaseNode) SetTint(tint color.RGBA) {
+	n.tint = tint
+}
+
 func (n *BaseNode) Position() *Vector3 {
 	return n.position
 }
 
 func (n *BaseNode) SetPosition(v *Vector3) {
-	n.dirty = true
+	n.markDirty()
 	n.position.Set2Components(v.X, v.Y)
 }
 
 func (n *BaseNode) SetPositionBy2Comp(x, y float64) {
-	n.dirty = true
+	n.markDirty()
 	n.position.Set2Components(x, y)
 }
 
 func (n *BaseNode) Scale() *Vector3 {
-	return n.position
+	return n.scale
 }
 
 func (n *BaseNode) SetScale(v *Vector3) {
-	n.dirty = true
+	n.markDirty()
 	n.scale.Set2Components(v.X, v.Y)
 }
 
 func (n *BaseNode) SetScaleUniform(s float64) {
-	n.dirty = true
+	n.markDirty()
 	n.scale.ScaleBy(s)
 }
 
@@ -103,16 +234,36 @@ func (n *BaseNode) Rotation() float64 {
 
 // +angle yields CW rotation
 func (n *BaseNode) SetRotation(angle float64) {
-	n.dirty = true
+	n.markDirty()
 	n.rotation = angle
 }
 
 // +angle yields CW rotation
 func (n *BaseNode) SetRotationByDegree(angle float64) {
-	n.dirty = true
+	n.markDirty()
 	n.rotation = angle * DegreeToRadians
 }
 
+func (n *BaseNode) Anchor() *Vector3 {
+	return n.anchor
+}
+
+func (n *BaseNode) SetAnchor(x, y float64) {
+	n.markDirty()
+	n.anchor.Set2Components(x, y)
+}
+
+func (n *BaseNode) ContentSize() *Vector3 {
+	return n.contentSize
+}
+
+// SetContentSize sets the geometry's size. The anchor stays at the same
+// normalized point so the pivot moves with the size.
+func (n *BaseNode) SetContentSize(width, height float64) {
+	n.markDirty()
+	n.contentSize.Set2Components(width, height)
+}
+
 func (n *BaseNode) Name() string {
 	return n.name
 }
@@ -121,18 +272,117 @@ func (n *BaseNode) SetName(s string) {
 	n.name = s
 }
 
+func (n *BaseNode) AddTag(tag string) {
+	if !n.HasTag(tag) {
+		n.tags = append(n.tags, tag)
+	}
+}
+
+func (n *BaseNode) RemoveTag(tag string) {
+	for i, t := range n.tags {
+		if t == tag {
+			n.tags = append(n.tags[:i], n.tags[i+1:]...)
+			return
+		}
+	}
+}
+
+func (n *BaseNode) HasTag(tag string) bool {
+	for _, t := range n.tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// Tags returns the node's tags in the order they were added. Don't modify
+// it.
+func (n *BaseNode) Tags() []string {
+	return n.tags
+}
+
+func (n *BaseNode) Parent() IGroupNode {
+	return n.parent
+}
+
+// setParent is only called by groups as the node is added or removed.
+// The node's world transform changes with its parent.
+func (n *BaseNode) setParent(parent IGroupNode) {
+	n.parent = parent
+	n.markDirty()
+}
+
+func (n *BaseNode) ZIndex() int {
+	return n.zIndex
+}
+
+// SetZIndex changes the node's order among its siblings. The parent
+// re-sorts before it next renders.
+func (n *BaseNode) SetZIndex(z int) {
+	n.zIndex = z
+	if n.parent != nil {
+		n.parent.childOrderChanged()
+	}
+}
+
+func (n *BaseNode) Layer() RenderLayer {
+	return n.layer
+}
+
+func (n *BaseNode) SetLayer(layer RenderLayer) {
+	n.layer = layer
+	layersChanged()
+}
+
 func (n *BaseNode) SetVisible() {
 	n.visible = true
+	layersChanged()
 }
 
 func (n *BaseNode) SetInvisible() {
 	n.visible = false
+	layersChanged()
 }
 
 func (n *BaseNode) IsVisible() bool {
 	return n.visible
 }
 
+// PointInside is always false for a node without geometry.
+func (n *BaseNode) PointInside(local *Vector3) bool {
+	return false
+}
+
+// SetPointerHandler registers a handler for pointer events over this node.
+func (n *BaseNode) SetPointerHandler(handler PointerHandler) {
+	n.pointerHandler = handler
+}
+
+func (n *BaseNode) PointerHandler() PointerHandler {
+	return n.pointerHandler
+}
+
+// OnEnter does nothing by default.
+func (n *BaseNode) OnEnter() {
+}
+
+// OnExit does nothing by default.
+func (n *BaseNode) OnExit() {
+}
+
+// OnAdded does nothing by default.
+func (n *BaseNode) OnAdded(parent IGroupNode) {
+}
+
+func (n *BaseNode) IsRunning() bool {
+	return n.running
+}
+
+func (n *BaseNode) setRunning(running bool) {
+	n.running = running
+}
+
 // Update node
 func (n *BaseNode) Update(dt float64) {
 	// fmt.Println("Node::Update")
@@ -148,7 +398,7 @@ func (n *BaseNode) Render(context *RenderContext) {
 	}
 
 	// Save context state first
-	context.Save()
+	context.push()
 
 	// if n.parent != nil {
 	// 	context.SetWithAT(n.parent.transform)
@@ -157,10 +407,12 @@ func (n *BaseNode) Render(context *RenderContext) {
 	// }
 
 	// Append this node's transform onto the context and then render
-	context.Transform(n.calcTransform())
+	context.enterNode(n)
 
 	// n.Draw(context)
-	n.drawer(context)
+	if context.ShouldDraw() {
+		n.drawer(context)
+	}
 
 	// Restores
 	context.Restore()
@@ -189,6 +441,13 @@ func (n *BaseNode) calcTransform() *AffineTransform {
 			n.transform.Scale(n.scale.X, n.scale.Y)
 		}
 
+		// Move the pivot to the origin first
+		px := n.anchor.X * n.contentSize.X
+		py := n.anchor.Y * n.contentSize.Y
+		if px != 0.0 || py != 0.0 {
+			n.transform.Translate(-px, -py)
+		}
+
 		//print("BaseNode.calcTransform\n ${transform}, tag:$tag");
 		n.dirty = false
 	}
@@ -218,11 +477,9 @@ type RectangleNode struct {
 func NewRectangleNode(parent IGroupNode, centered, autoAdd bool) INode {
 	g := new(RectangleNode)
 	g.Initialize()
-	g.centered = centered
-	g.parent = parent
 
 	if autoAdd {
-		g.parent.Add(g)
+		parent.Add(g)
 	}
 
 	g.vertices = make([]*Vector3, 4)
@@ -231,23 +488,49 @@ func NewRectangleNode(parent IGroupNode, centered, autoAdd bool) INode {
 	g.vertices[2] = NewVector3()
 	g.vertices[3] = NewVector3()
 
-	if centered {
-		g.vertices[0].Set2Components(-0.5, -0.5)
-		g.vertices[1].Set2Components(-0.5, 0.5)
-		g.vertices[2].Set2Components(0.5, 0.5)
-		g.vertices[3].Set2Components(0.5, -0.5)
-	} else {
-		g.vertices[0].Set2Components(0.0, 0.0)
-		g.vertices[1].Set2Components(0.0, 1.0)
-		g.vertices[2].Set2Components(1.0, 1.0)
-		g.vertices[3].Set2Components(1.0, 0.0)
-	}
+	g.centered = centered
+
+	// A unit square by default, scale it or change its size
+	g.SetContentSize(1.0, 1.0)
 
 	g.drawer = g.Draw
 
 	return g
 }
 
+// SetCentered places the rectangle either centered on the origin or
+// with its top-left corner at the origin. The anchor is measured from the
+// local origin either way so a centered rectangle normally keeps the
+// default 0,0 anchor.
+func (n *RectangleNode) SetCentered(centered bool) {
+	n.centered = centered
+	n.SetContentSize(n.contentSize.X, n.contentSize.Y)
+}
+
+// SetContentSize resizes the rectangle's geometry.
+func (n *RectangleNode) SetContentSize(width, height float64) {
+	n.BaseNode.SetContentSize(width, height)
+
+	x, y := 0.0, 0.0
+	if n.centered {
+		x, y = -width/2.0, -height/2.0
+	}
+
+	n.vertices[0].Set2Components(x, y)
+	n.vertices[1].Set2Components(x, y+height)
+	n.vertices[2].Set2Components(x+width, y+height)
+	n.vertices[3].Set2Components(x+width, y)
+}
+
+func (n *RectangleNode) IsCentered() bool {
+	return n.centered
+}
+
+// Properties returns the rectangle specific state for serialization.
+func (n *RectangleNode) Properties() Properties {
+	return Properties{"centered": n.centered}
+}
+
 func (n *RectangleNode) Update(dt float64) {
 }
 
@@ -260,6 +543,16 @@ func (n *RectangleNode) Render(context *RenderContext) {
 	// n.Draw(context)
 }
 
+// PointInside tests the point against the rectangle's vertices.
+func (n *RectangleNode) PointInside(local *Vector3) bool {
+	minX := n.vertices[0].X
+	minY := n.vertices[0].Y
+	maxX := n.vertices[2].X
+	maxY := n.vertices[2].Y
+
+	return local.X >= minX && local.X <= maxX && local.Y >= minY && local.Y <= maxY
+}
+
 func (n *RectangleNode) Draw(context *RenderContext) {
 	context.DrawPolygon(n.vertices, n.SolidColor)
 }
@@ -0,0 +1,367 @@
+package engine
+
+import (
+	"fmt"
+	"image/color"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Properties is a loosely typed property map used to create nodes by
+// type name. Values can be native Go values, decoded JSON/YAML values or
+// strings, for example, typed into a debug console. The getters convert
+// as needed.
+type Properties map[string]interface{}
+
+// NodeFactory creates a node of a registered type from its type specific
+// properties. parent is the group the node is destined for; factories
+// don't add the node to it.
+type NodeFactory func(parent IGroupNode, props Properties) (INode, error)
+
+type nodeType struct {
+	name    string
+	factory NodeFactory
+}
+
+var (
+	nodeTypesByName = map[string]*nodeType{}
+	nodeTypesByType = map[reflect.Type]*nodeType{}
+)
+
+func init() {
+	RegisterNodeType("Group", &GroupNode{}, func(parent IGroupNode, props Properties) (INode, error) {
+		return NewGroupNode(parent, false), nil
+	})
+	RegisterNodeType("Rectangle", &RectangleNode{}, func(parent IGroupNode, props Properties) (INode, error) {
+		centered, err := props.Bool("centered", false)
+		if err != nil {
+			return nil, err
+		}
+		return NewRectangleNode(parent, centered, false), nil
+	})
+}
+
+// RegisterNodeType makes a node type available by name to scene files,
+// consoles and editors. prototype is any value of the type, for example,
+// &MyNode{}, and is used to find the name when serializing.
+func RegisterNodeType(name string, prototype INode, factory NodeFactory) {
+	t := &nodeType{name: name, factory: factory}
+	nodeTypesByName[name] = t
+	nodeTypesByType[reflect.TypeOf(prototype)] = t
+}
+
+// RegisteredNodeTypes returns the registered type names, sorted.
+func RegisteredNodeTypes() []string {
+	names := make([]string, 0, len(nodeTypesByName))
+	for name := range nodeTypesByName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NodeTypeName returns the registered name of n's type.
+func NodeTypeName(n INode) (string, error) {
+	t, ok := nodeTypesByType[reflect.TypeOf(n)]
+	if !ok {
+		return "", fmt.Errorf("node type %T isn't registered", n)
+	}
+	return t.name, nil
+}
+
+// CreateNode instantiates a registered node type by name. Common
+// properties, see ApplyProperties, are applied after the factory runs.
+// If parent isn't nil the node is added to it.
+func CreateNode(typeName string, parent IGroupNode, props Properties) (INode, error) {
+	n, err := newNode(typeName, parent, props)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ApplyProperties(n, props)
+	if err != nil {
+		return nil, err
+	}
+
+	if parent != nil {
+		parent.Add(n)
+	}
+
+	return n, nil
+}
+
+// newNode runs the factory only.
+func newNode(typeName string, parent IGroupNode, props Properties) (INode, error) {
+	t, ok := nodeTypesByName[typeName]
+	if !ok {
+		return nil, fmt.Errorf("unknown node type '%s'", typeName)
+	}
+
+	if props == nil {
+		props = Properties{}
+	}
+
+	return t.factory(parent, props)
+}
+
+// ApplyProperties sets the properties every node has: "name", "x", "y",
+// "scale" (uniform), "scaleX", "scaleY", "rotation" (degrees),
+// "anchorX", "anchorY", "width", "height" (content size), "visible",
+// "color" ("#rrggbbaa"), "opacity", "tint", "zIndex", "layer" and "tags"
+// (a list or comma separated string). Missing properties are left
+// unchanged and their setters aren't called.
+func ApplyProperties(n INode, props Properties) error {
+	if name, ok := props["name"]; ok {
+		n.SetName(fmt.Sprint(name))
+	}
+
+	if props.has("x", "y") {
+		p := n.Position()
+		x, err := props.Float("x", p.X)
+		if err != nil {
+			return err
+		}
+		y, err := props.Float("y", p.Y)
+		if err != nil {
+			return err
+		}
+		n.SetPositionBy2Comp(x, y)
+	}
+
+	if props.has("scale", "scaleX", "scaleY") {
+		s := n.Scale()
+		sx, err := props.Float("scale", s.X)
+		if err != nil {
+			return err
+		}
+		sy, _ := props.Float("scale", s.Y)
+		sx, err = props.Float("scaleX", sx)
+		if err != nil {
+			return err
+		}
+		sy, err = props.Float("scaleY", sy)
+		if err != nil {
+			return err
+		}
+		n.SetScale(NewVector3With2Components(sx, sy))
+	}
+
+	if props.has("width", "height") {
+		size := n.ContentSize()
+		w, err := props.Float("width", size.X)
+		if err != nil {
+			return err
+		}
+		h, err := props.Float("height", size.Y)
+		if err != nil {
+			return err
+		}
+		n.SetContentSize(w, h)
+	}
+
+	if props.has("anchorX", "anchorY") {
+		anchor := n.Anchor()
+		ax, err := props.Float("anchorX", anchor.X)
+		if err != nil {
+			return err
+		}
+		ay, err := props.Float("anchorY", anchor.Y)
+		if err != nil {
+			return err
+		}
+		n.SetAnchor(ax, ay)
+	}
+
+	if props.has("rotation") {
+		rotation, err := props.Float("rotation", 0.0)
+		if err != nil {
+			return err
+		}
+		n.SetRotationByDegree(rotation)
+	}
+
+	if props.has("visible") {
+		visible, err := props.Bool("visible", true)
+		if err != nil {
+			return err
+		}
+		if visible {
+			n.SetVisible()
+		} else {
+			n.SetInvisible()
+		}
+	}
+
+	if props.has("color") {
+		c, err := props.Color("color", n.Color())
+		if err != nil {
+			return err
+		}
+		n.SetColor(c)
+	}
+
+	if props.has("opacity") {
+		opacity, err := props.Float("opacity", 1.0)
+		if err != nil {
+			return err
+		}
+		n.SetOpacity(opacity)
+	}
+
+	if props.has("tint") {
+		tint, err := props.Color("tint", n.Tint())
+		if err != nil {
+			return err
+		}
+		n.SetTint(tint)
+	}
+
+	if props.has("zIndex") {
+		z, err := props.Int("zIndex", 0)
+		if err != nil {
+			return err
+		}
+		n.SetZIndex(z)
+	}
+
+	if props.has("layer") {
+		layer, err := props.Int("layer", int(LayerInherit))
+		if err != nil {
+			return err
+		}
+		n.SetLayer(RenderLayer(layer))
+	}
+
+	tags, err := props.Strings("tags")
+	if err != nil {
+		return err
+	}
+	for _, tag := range tags {
+		n.AddTag(tag)
+	}
+
+	return nil
+}
+
+// has is true if any of keys is present.
+func (p Properties) has(keys ...string) bool {
+	for _, key := range keys {
+		if _, ok := p[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Float returns a numeric property or def if missing.
+func (p Properties) Float(key string, def float64) (float64, error) {
+	v, ok := p[key]
+	if !ok {
+		return def, nil
+	}
+
+	switch t := v.(type) {
+	case float64:
+		return t, nil
+	case float32:
+		return float64(t), nil
+	case int:
+		return float64(t), nil
+	case int64:
+		return float64(t), nil
+	case string:
+		f, err := strconv.ParseFloat(t, 64)
+		if err != nil {
+			return def, fmt.Errorf("property '%s': %v", key, err)
+		}
+		return f, nil
+	}
+
+	return def, fmt.Errorf("property '%s' must be a number, got %T", key, v)
+}
+
+// Int returns an integer property or def if missing.
+func (p Properties) Int(key string, def int) (int, error) {
+	f, err := p.Float(key, float64(def))
+	return int(f), err
+}
+
+// Bool returns a boolean property or def if missing.
+func (p Properties) Bool(key string, def bool) (bool, error) {
+	v, ok := p[key]
+	if !ok {
+		return def, nil
+	}
+
+	switch t := v.(type) {
+	case bool:
+		return t, nil
+	case string:
+		b, err := strconv.ParseBool(t)
+		if err != nil {
+			return def, fmt.Errorf("property '%s': %v", key, err)
+		}
+		return b, nil
+	}
+
+	return def, fmt.Errorf("property '%s' must be a bool, got %T", key, v)
+}
+
+// String returns a property formatted as a string or def if missing.
+func (p Properties) String(key string, def string) string {
+	v, ok := p[key]
+	if !ok {
+		return def
+	}
+	return fmt.Sprint(v)
+}
+
+// Strings returns a list property, either a list or a comma separated
+// string, or nil if missing.
+func (p Properties) Strings(key string) ([]string, error) {
+	v, ok := p[key]
+	if !ok {
+		return nil, nil
+	}
+
+	switch t := v.(type) {
+	case []string:
+		return t, nil
+	case []interface{}:
+		list := make([]string, len(t))
+		for i, e := range t {
+			list[i] = fmt.Sprint(e)
+		}
+		return list, nil
+	case string:
+		var list []string
+		for _, s := range strings.Split(t, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				list = append(list, s)
+			}
+		}
+		return list, nil
+	}
+
+	return nil, fmt.Errorf("property '%s' must be a list, got %T", key, v)
+}
+
+// Color returns a color property, either a color.RGBA or "#rrggbbaa", or
+// def if missing.
+func (p Properties) Color(key string, def color.RGBA) (color.RGBA, error) {
+	v, ok := p[key]
+	if !ok {
+		return def, nil
+	}
+
+	switch t := v.(type) {
+	case color.RGBA:
+		return t, nil
+	case string:
+		return ParseColor(t)
+	}
+
+	return def, fmt.Errorf("property '%s' must be a color, got %T", key, v)
+}
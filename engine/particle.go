@@ -2,6 +2,7 @@ package engine
 
 import (
 	"image/color"
+	"math"
 )
 
 // Particle is a single particle
@@ -13,9 +14,11 @@ type Particle struct {
 	Duration  float64
 	acculTime float64
 
-	// Colors are lerped
+	// Colors are interpolated from start to end with Easing
 	StartColor, EndColor color.RGBA
 	RenderColor          color.RGBA
+	Easing               EasingFunction
+	color                *Tween
 
 	IsAlive bool
 }
@@ -24,9 +27,17 @@ type Particle struct {
 func NewParticle() *Particle {
 	p := new(Particle)
 	p.Duration = 1.0
+	p.Easing = LinearEasing
 
 	p.Position = NewVector3()
 	p.Velocity = NewVector3()
+
+	// The tween reads the colors as it runs so particles can be
+	// retriggered without allocating
+	p.color = NewTweenFunc(0.0, 1.0, p.Duration, func(t float64) {
+		p.RenderColor = lerpParticleColor(p.StartColor, p.EndColor, t)
+	})
+
 	return p
 }
 
@@ -36,19 +47,14 @@ func (ps *Particle) Update(dt float64) {
 		return
 	}
 
-	re := LinearEasing(ps.acculTime, float64(ps.StartColor.R), -(float64(ps.StartColor.R) - float64(ps.EndColor.R)), ps.Duration)
-	ps.RenderColor.R = uint8(re)
-
-	gr := LinearEasing(ps.acculTime, float64(ps.StartColor.G), -(float64(ps.StartColor.G) - float64(ps.EndColor.G)), ps.Duration)
-	ps.RenderColor.G = uint8(gr)
-
-	bl := LinearEasing(ps.acculTime, float64(ps.StartColor.B), -(float64(ps.StartColor.B) - float64(ps.EndColor.B)), ps.Duration)
-	ps.RenderColor.B = uint8(bl)
+	if ps.acculTime == 0.0 {
+		ps.activate()
+	}
 
+	ps.color.Seek(ps.acculTime)
 	ps.RenderColor.A = ps.StartColor.A
 
 	ps.acculTime += float64(dt)
-	// fmt.Printf("%f, %f, %f: %f\n", re, gr, bl, ps.acculTime)
 
 	ps.Position.Add(ps.Velocity)
 
@@ -58,3 +64,30 @@ func (ps *Particle) Update(dt float64) {
 		ps.IsAlive = false
 	}
 }
+
+// activate restarts the color tween for the particle's current duration
+// and easing once the particle has been triggered.
+func (ps *Particle) activate() {
+	easing := ps.Easing
+	if easing == nil {
+		easing = LinearEasing
+	}
+
+	ps.color.SetDuration(ps.Duration)
+	ps.color.SetEasing(easing)
+	ps.color.Restart()
+}
+
+// lerpParticleColor truncates, and clamps easings that overshoot, as
+// particles always have.
+func lerpParticleColor(from, to color.RGBA, p float64) color.RGBA {
+	channel := func(f, t uint8) uint8 {
+		return uint8(math.Max(0.0, math.Min(lerp(float64(f), float64(t), p), 255.0)))
+	}
+	return color.RGBA{
+		R: channel(from.R, to.R),
+		G: channel(from.G, to.G),
+		B: channel(from.B, to.B),
+		A: channel(from.A, to.A),
+	}
+}
@@ -0,0 +1,44 @@
+package engine
+
+import (
+	"image"
+)
+
+// FrameStats carries per frame timing and pointer information that a
+// Platform may display when presenting a frame.
+type FrameStats struct {
+	// ElapsedTime is the frame period in milliseconds
+	ElapsedTime float64
+	// LoopTime is how long the last loop iteration took in milliseconds
+	LoopTime float64
+
+	MouseX int32
+	MouseY int32
+}
+
+// Platform is the windowing/presentation backend an Engine runs on.
+// The SDL platform opens a window and presents the drawing buffer through
+// a streaming texture. The headless platform presents nothing and is
+// driven by synthetic events, which makes it suitable for tests.
+type Platform interface {
+	// Initialize creates any native resources, for example, a window.
+	Initialize(title string, width, height int32) error
+	// SetVSync selects whether presenting waits for the display's vertical
+	// sync. Platforms that create their renderer in Initialize return
+	// ErrVSyncAfterInitialize if called afterwards.
+	SetVSync(enabled bool) error
+	// Configure creates any overlay resources that require a font.
+	Configure(font *Font) error
+
+	// PumpEvents polls for input and window events, for example focus
+	// changes, and posts them to the engine as typed events.
+	PumpEvents(engine *Engine)
+
+	// Present displays the drawing buffer.
+	Present(pixels *image.RGBA, stats *FrameStats)
+	// Delay sleeps for the given milliseconds.
+	Delay(ms uint32)
+
+	// Close releases any native resources.
+	Close()
+}
@@ -0,0 +1,165 @@
+package engine
+
+// PointerEventType identifies a pointer interaction with a node.
+type PointerEventType int
+
+const (
+	// PointerEnter is sent when the pointer moves over a node.
+	PointerEnter PointerEventType = iota
+	// PointerLeave is sent when the pointer moves off a node.
+	PointerLeave
+	// PointerDown is sent when a button is pressed over a node.
+	PointerDown
+	// PointerUp is sent to the node that received the PointerDown.
+	PointerUp
+	// PointerClick follows PointerUp if the pointer is still over the node.
+	PointerClick
+	// PointerDrag is sent to the node that received the PointerDown while
+	// the pointer moves with the button held, even if it leaves the node.
+	PointerDrag
+)
+
+const (
+	// PriorityPointer places node pointer handling ahead of default
+	// listeners so a node can consume the clicks it handles.
+	PriorityPointer = 100
+)
+
+// PointerEvent is delivered to a node's PointerHandler.
+type PointerEvent struct {
+	Type PointerEventType
+	Node INode
+
+	// Button is valid for PointerDown, PointerUp, PointerClick and PointerDrag
+	Button MouseButton
+
+	// X,Y is the pointer position in window coordinates
+	X, Y float64
+	// DX,DY is the movement since the last PointerDrag
+	DX, DY float64
+}
+
+// PointerHandler receives pointer events for a node.
+type PointerHandler func(event *PointerEvent)
+
+// PointerDispatcher hit tests mouse input against a scene graph and
+// delivers pointer events to the top-most visible node under the cursor.
+type PointerDispatcher struct {
+	root func() IGroupNode
+
+	// Node currently under the pointer
+	hover INode
+	// Node that received PointerDown, per button
+	captured [maxMouseButtons]INode
+
+	x, y float64
+}
+
+// NewPointerDispatcher creates a dispatcher listening to input. root is
+// called on each event so the dispatcher follows root changes.
+func NewPointerDispatcher(input *Input, root func() IGroupNode) *PointerDispatcher {
+	d := new(PointerDispatcher)
+	d.root = root
+	input.AddListener(PriorityPointer, d.handleEvent)
+	return d
+}
+
+// Hover returns the node under the pointer, or nil.
+func (d *PointerDispatcher) Hover() INode {
+	return d.hover
+}
+
+func (d *PointerDispatcher) handleEvent(event IEvent) {
+	switch t := event.(type) {
+	case *MouseMotionEvent:
+		d.moved(float64(t.X), float64(t.Y))
+	case *MouseButtonEvent:
+		if int(t.Button) >= maxMouseButtons {
+			return
+		}
+		d.moved(float64(t.X), float64(t.Y))
+
+		if t.Type() == EventMouseButtonDown {
+			d.down(t)
+		} else {
+			d.up(t)
+		}
+	}
+}
+
+func (d *PointerDispatcher) moved(x, y float64) {
+	dx := x - d.x
+	dy := y - d.y
+	d.x = x
+	d.y = y
+
+	hit := HitTest(d.root(), x, y)
+
+	if hit != d.hover {
+		if d.hover != nil {
+			d.send(d.hover, PointerLeave, 0, 0, 0)
+		}
+		d.hover = hit
+		if hit != nil {
+			d.send(hit, PointerEnter, 0, 0, 0)
+		}
+	}
+
+	if dx == 0.0 && dy == 0.0 {
+		return
+	}
+
+	for b, n := range d.captured {
+		if n != nil {
+			d.send(n, PointerDrag, MouseButton(b), dx, dy)
+		}
+	}
+}
+
+func (d *PointerDispatcher) down(event *MouseButtonEvent) {
+	if d.hover == nil {
+		return
+	}
+
+	d.captured[event.Button] = d.hover
+	if d.send(d.hover, PointerDown, event.Button, 0, 0) {
+		event.Consume()
+	}
+}
+
+func (d *PointerDispatcher) up(event *MouseButtonEvent) {
+	n := d.captured[event.Button]
+	if n == nil {
+		return
+	}
+	d.captured[event.Button] = nil
+
+	handled := d.send(n, PointerUp, event.Button, 0, 0)
+	if n == d.hover {
+		d.send(n, PointerClick, event.Button, 0, 0)
+	}
+
+	if handled {
+		event.Consume()
+	}
+}
+
+// send delivers an event and reports whether the node has a handler.
+func (d *PointerDispatcher) send(n INode, eventType PointerEventType, button MouseButton, dx, dy float64) bool {
+	handler := n.PointerHandler()
+	if handler == nil {
+		return false
+	}
+
+	handler(&PointerEvent{
+		Type:   eventType,
+		Node:   n,
+		Button: button,
+		X:      d.x,
+		Y:      d.y,
+		DX:     dx,
+		DY:     dy,
+	})
+
+	return true
+}
@@ -0,0 +1,128 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Prefab is a named template subtree that can be instantiated many times.
+// The template is captured when the prefab is created; later changes to
+// the nodes it was made from don't affect it.
+type Prefab struct {
+	name     string
+	template *NodeDescriptor
+}
+
+var prefabs = map[string]*Prefab{}
+
+// NewPrefab captures template and its subtree. Every node's type must be
+// registered with RegisterNodeType.
+func NewPrefab(name string, template INode) (*Prefab, error) {
+	d, err := DescribeNode(template)
+	if err != nil {
+		return nil, err
+	}
+	return NewPrefabFromDescriptor(name, d), nil
+}
+
+// NewPrefabFromDescriptor creates a prefab from a copy of d.
+func NewPrefabFromDescriptor(name string, d *NodeDescriptor) *Prefab {
+	p := new(Prefab)
+	p.name = name
+	p.template = d.Copy()
+	return p
+}
+
+// LoadPrefab creates a prefab from a scene file, see LoadScene.
+func LoadPrefab(name, path string) (*Prefab, error) {
+	d, err := LoadSceneDescriptor(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewPrefabFromDescriptor(name, d), nil
+}
+
+func (p *Prefab) Name() string {
+	return p.name
+}
+
+// Instantiate builds a new copy of the template and, if parent isn't nil,
+// adds it to parent. overrides, which may be nil, change properties per
+// instance. They are keyed by the path of names from the instance root,
+// for example, "turret/barrel", with "" for the root itself. Common
+// properties are applied as ApplyProperties does; any others are passed
+// to the node type's factory.
+func (p *Prefab) Instantiate(parent IGroupNode, overrides map[string]Properties) (INode, error) {
+	d := p.template
+
+	byDescriptor := map[*NodeDescriptor]Properties{}
+	if len(overrides) > 0 {
+		d = d.Copy()
+
+		for path, props := range overrides {
+			target := findDescriptor(d, path)
+			if target == nil {
+				return nil, fmt.Errorf("prefab '%s' has no node '%s'", p.name, path)
+			}
+
+			if target.Properties == nil {
+				target.Properties = Properties{}
+			}
+			for k, v := range props {
+				target.Properties[k] = v
+			}
+			byDescriptor[target] = props
+		}
+	}
+
+	return buildNode(d, parent, func(nd *NodeDescriptor, n INode) error {
+		if props, ok := byDescriptor[nd]; ok {
+			return ApplyProperties(n, props)
+		}
+		return nil
+	})
+}
+
+// findDescriptor follows a path of names below d.
+func findDescriptor(d *NodeDescriptor, path string) *NodeDescriptor {
+	for _, name := range strings.Split(strings.Trim(path, "/"), "/") {
+		if name == "" {
+			continue
+		}
+
+		var next *NodeDescriptor
+		for _, cd := range d.Children {
+			if cd.Name == name {
+				next = cd
+				break
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		d = next
+	}
+
+	return d
+}
+
+// RegisterPrefab makes a prefab available by name.
+func RegisterPrefab(p *Prefab) {
+	prefabs[p.name] = p
+}
+
+// LookupPrefab returns a registered prefab.
+func LookupPrefab(name string) (*Prefab, bool) {
+	p, ok := prefabs[name]
+	return p, ok
+}
+
+// InstantiatePrefab instantiates a registered prefab, see
+// Prefab.Instantiate.
+func InstantiatePrefab(name string, parent IGroupNode, overrides map[string]Properties) (INode, error) {
+	p, ok := prefabs[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown prefab '%s'", name)
+	}
+	return p.Instantiate(parent, overrides)
+}
@@ -0,0 +1,227 @@
+package engine
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Query paths select nodes below a root by name, one segment per level,
+// separated by "/":
+//
+//	whiteGroup/orangeGroup     child "orangeGroup" of child "whiteGroup"
+//	whiteGroup/*               every child of "whiteGroup"
+//	whiteGroup/orange*         glob, as path.Match, over child names
+//	**/OrangeRect              "OrangeRect" at any depth
+//	enemies/@boss              children of "enemies" tagged "boss"
+//
+// "**" matches zero or more levels and, last, every descendant. The root
+// is only matched by an empty query.
+
+type segmentKind int
+
+const (
+	segmentName segmentKind = iota
+	segmentGlob
+	segmentTag
+	segmentAnyDepth
+)
+
+type querySegment struct {
+	kind    segmentKind
+	pattern string
+}
+
+func (s *querySegment) matches(n INode) bool {
+	switch s.kind {
+	case segmentName:
+		return n.Name() == s.pattern
+	case segmentGlob:
+		ok, _ := path.Match(s.pattern, n.Name())
+		return ok
+	case segmentTag:
+		return n.HasTag(s.pattern)
+	}
+	return false
+}
+
+func parseQuery(query string) ([]querySegment, error) {
+	var segments []querySegment
+
+	for _, s := range strings.Split(strings.Trim(query, "/"), "/") {
+		switch {
+		case s == "":
+			continue
+		case s == "**":
+			// Consecutive "**"s are the same as one
+			if n := len(segments); n == 0 || segments[n-1].kind != segmentAnyDepth {
+				segments = append(segments, querySegment{kind: segmentAnyDepth})
+			}
+		case strings.HasPrefix(s, "@"):
+			segments = append(segments, querySegment{kind: segmentTag, pattern: s[1:]})
+		case strings.ContainsAny(s, `*?[\`):
+			if _, err := path.Match(s, ""); err != nil {
+				return nil, fmt.Errorf("bad query segment '%s': %v", s, err)
+			}
+			segments = append(segments, querySegment{kind: segmentGlob, pattern: s})
+		default:
+			segments = append(segments, querySegment{kind: segmentName, pattern: s})
+		}
+	}
+
+	return segments, nil
+}
+
+// NodeIterator lazily yields the nodes matching a query in tree order.
+//
+//	it := engine.FindByTag(root, "enemy")
+//	for it.Next() {
+//		it.Node().SetInvisible()
+//	}
+type NodeIterator struct {
+	segments []querySegment
+	pending  []queryState
+	// Only needed when more than one "**" can reach the same node
+	seen map[INode]bool
+
+	node INode
+}
+
+// queryState is a node with the segments still to match below it.
+type queryState struct {
+	node    INode
+	segment int
+}
+
+func newNodeIterator(root INode, segments []querySegment) *NodeIterator {
+	it := new(NodeIterator)
+	it.segments = segments
+	it.pending = []queryState{{root, 0}}
+
+	anyDepth := 0
+	for _, s := range segments {
+		if s.kind == segmentAnyDepth {
+			anyDepth++
+		}
+	}
+	if anyDepth > 1 {
+		it.seen = map[INode]bool{}
+	}
+
+	return it
+}
+
+// Next advances to the next matching node, returning false when there
+// are no more.
+func (it *NodeIterator) Next() bool {
+	for len(it.pending) > 0 {
+		last := len(it.pending) - 1
+		state := it.pending[last]
+		it.pending = it.pending[:last]
+
+		if state.segment == len(it.segments) {
+			if it.seen != nil {
+				if it.seen[state.node] {
+					continue
+				}
+				it.seen[state.node] = true
+			}
+			it.node = state.node
+			return true
+		}
+
+		g, ok := state.node.(IGroupNode)
+		if !ok {
+			continue
+		}
+
+		// Children are pushed last to first so they pop in tree order
+		seg := &it.segments[state.segment]
+		children := g.Children()
+		for i := len(children) - 1; i >= 0; i-- {
+			child := children[i]
+
+			if seg.kind != segmentAnyDepth {
+				if seg.matches(child) {
+					it.pending = append(it.pending, queryState{child, state.segment + 1})
+				}
+				continue
+			}
+
+			// "**" carries on below the child...
+			it.pending = append(it.pending, queryState{child, state.segment})
+
+			// ...after the child is matched against what follows it. A
+			// trailing "**" matches every descendant.
+			next := state.segment + 1
+			if next == len(it.segments) {
+				it.pending = append(it.pending, queryState{child, next})
+			} else if it.segments[next].matches(child) {
+				it.pending = append(it.pending, queryState{child, next + 1})
+			}
+		}
+	}
+
+	it.node = nil
+	return false
+}
+
+// Node returns the current node.
+func (it *NodeIterator) Node() INode {
+	return it.node
+}
+
+// Collect drains the iterator into a slice.
+func (it *NodeIterator) Collect() []INode {
+	var nodes []INode
+	for it.Next() {
+		nodes = append(nodes, it.Node())
+	}
+	return nodes
+}
+
+// Query returns an iterator over the nodes below root matching the path
+// query.
+func Query(root INode, query string) (*NodeIterator, error) {
+	segments, err := parseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	return newNodeIterator(root, segments), nil
+}
+
+// QueryFirst returns the first node matching the path query, or nil.
+func QueryFirst(root INode, query string) (INode, error) {
+	it, err := Query(root, query)
+	if err != nil || !it.Next() {
+		return nil, err
+	}
+	return it.Node(), nil
+}
+
+// FindByName returns the first node below root, in tree order, with the
+// given name, or nil. Unlike Query the name is never treated as a pattern.
+func FindByName(root INode, name string) INode {
+	it := FindAllByName(root, name)
+	if it.Next() {
+		return it.Node()
+	}
+	return nil
+}
+
+// FindAllByName returns an iterator over every node below root with the
+// given name.
+func FindAllByName(root INode, name string) *NodeIterator {
+	return newNodeIterator(root, []querySegment{
+		{kind: segmentAnyDepth},
+		{kind: segmentName, pattern: name},
+	})
+}
+
+// FindByTag returns an iterator over every node below root with the tag.
+func FindByTag(root INode, tag string) *NodeIterator {
+	return newNodeIterator(root, []querySegment{
+		{kind: segmentAnyDepth},
+		{kind: segmentTag, pattern: tag},
+	})
+}
@@ -3,6 +3,8 @@ package engine
 import (
 	"image"
 	"image/color"
+
+	"github.com/fogleman/gg"
 )
 
 const (
@@ -14,15 +16,54 @@ type RenderContext struct {
 	tPoints []*Vector3
 
 	dc *gg.Context
+	// Target the context draws into
+	target *image.RGBA
 	// Current context
-	context      *AffineTransform
-	contextState *Stack
+	context *AffineTransform
+
+	// Inherited node state, saved and restored with the transform
+	state renderState
+	// Saved transforms and states, see push
+	frames []renderFrame
+
+	// Interpolation alpha between the previous and current
+	// simulation step, see FixedTimestep.
+	alpha float64
+
+	// Layer drawn by the current pass when there is more than one
+	pass      RenderLayer
+	multiPass bool
+}
+
+// renderState is the state a node inherits from its ancestors.
+type renderState struct {
+	// World version of the current context transform, see world.go.
+	// Zero is the identity the engine starts each frame with.
+	version uint64
+	// Layer of the node being rendered, see render_layers.go
+	layer RenderLayer
+	// Accumulated opacity and tint, as 0-1 multipliers
+	opacity float64
+	tint    [3]float64
+}
+
+// renderFrame is what Save and the scene graph's traversal restore.
+type renderFrame struct {
+	transform AffineTransform
+	state     renderState
+	// The gg context's state was pushed too
+	ggPushed bool
+}
+
+func newRenderState() renderState {
+	return renderState{layer: LayerWorld, opacity: 1.0, tint: [3]float64{1.0, 1.0, 1.0}}
 }
 
 func NewRenderContext(image *image.RGBA) *RenderContext {
 	c := new(RenderContext)
-	c.contextState = NewStack(100)
+	c.state = newRenderState()
 	c.dc = gg.NewContextForRGBA(image)
+	c.target = image
 
 	c.context = NewAffineTransform()
 	c.tPoints = make([]*Vector3, MaxTranformedVertices)
@@ -36,37 +77,150 @@ func NewRenderContext(image *image.RGBA) *RenderContext {
 
 func (c *RenderContext) Set(at *AffineTransform) {
 	c.context = at
+	c.state.version = nextWorldVersion()
 }
 
 func (c *RenderContext) TransformContext() *AffineTransform {
 	return c.context
 }
 
+// RenderContext returns the gg context for drawing directly. Any state set
+// on it is restored when the node being rendered is finished.
 func (c *RenderContext) RenderContext() *gg.Context {
+	// Nodes only save the gg state once they ask for it
+	if n := len(c.frames); n > 0 && !c.frames[n-1].ggPushed {
+		c.dc.Push()
+		c.frames[n-1].ggPushed = true
+	}
 	return c.dc
 }
 
+// Target returns the image the context draws into.
+func (c *RenderContext) Target() *image.RGBA {
+	return c.target
+}
+
+// SetInterpolationAlpha is called by the engine prior to rendering a frame.
+func (c *RenderContext) SetInterpolationAlpha(alpha float64) {
+	c.alpha = alpha
+}
+
+// InterpolationAlpha returns the fraction [0, 1) of a simulation step
+// that has elapsed since the last update. Nodes can use it to blend
+// between their previous and current state.
+func (c *RenderContext) InterpolationAlpha() float64 {
+	return c.alpha
+}
+
+// Save pushes the transform, inherited node state and gg state.
 func (c *RenderContext) Save() {
+	c.push()
 	c.dc.Push()
+	c.frames[len(c.frames)-1].ggPushed = true
+}
 
-	t := AffinePool.Pop()
-	t.SetWithAT(c.context) // Copy current context and push
-	c.contextState.Push(t)
+// push saves the transform and inherited node state. It is what nodes use
+// while rendering, the gg state is only pushed if a node asks for the gg
+// context. Frames are kept by value so saving doesn't allocate.
+func (c *RenderContext) push() {
+	c.frames = append(c.frames, renderFrame{transform: *c.context, state: c.state})
 }
 
 func (c *RenderContext) Transform(at *AffineTransform) {
 	AffineTransformMultiplyTo(at, c.context)
+	// An arbitrary transform yields a context no node has cached
+	c.state.version = nextWorldVersion()
 }
 
+// enterNode concatenates a node's local transform onto the context and
+// combines the node's layer, opacity and tint with those inherited.
+//
+// If neither the node nor the context it is concatenated onto has changed
+// since the node was last transformed the node's cached world transform
+// is reused rather than multiplied again. This is the same cache that
+// NodeToWorld uses.
+func (c *RenderContext) enterNode(n *BaseNode) {
+	cache := n.world
+	if cache.version == 0 || cache.localVersion != n.localVersion || cache.parentVersion != c.state.version {
+		AffineTransformMultiply(n.calcTransform(), c.context, cache.transform)
+
+		cache.localVersion = n.localVersion
+		cache.parentVersion = c.state.version
+		cache.version = nextWorldVersion()
+	}
+
+	c.context.SetWithAT(cache.transform)
+	c.state.version = cache.version
+
+	if n.layer != LayerInherit {
+		c.state.layer = n.layer
+	}
+
+	c.state.opacity *= n.opacity
+	c.state.tint[0] *= float64(n.tint.R) / 255.0
+	c.state.tint[1] *= float64(n.tint.G) / 255.0
+	c.state.tint[2] *= float64(n.tint.B) / 255.0
+}
+
+// beginPass starts rendering a tree for layer. The current transform,
+// which may not be the identity, is kept.
+func (c *RenderContext) beginPass(layer RenderLayer, multiPass bool) {
+	version := c.state.version
+	c.state = newRenderState()
+	c.state.version = version
+	c.pass = layer
+	c.multiPass = multiPass
+}
+
+// Opacity returns the opacity accumulated down to the node being rendered.
+func (c *RenderContext) Opacity() float64 {
+	return c.state.opacity
+}
+
+// ModulateColor applies the accumulated tint and opacity to a color.
+// Nodes drawing without DrawPolygon should pass their colors through it.
+func (c *RenderContext) ModulateColor(col color.RGBA) color.RGBA {
+	s := &c.state
+	if s.opacity == 1.0 && s.tint == [3]float64{1.0, 1.0, 1.0} {
+		return col
+	}
+
+	// color.RGBA is alpha premultiplied so opacity scales every channel
+	return color.RGBA{
+		R: uint8(float64(col.R) * s.tint[0] * s.opacity),
+		G: uint8(float64(col.G) * s.tint[1] * s.opacity),
+		B: uint8(float64(col.B) * s.tint[2] * s.opacity),
+		A: uint8(float64(col.A) * s.opacity),
+	}
+}
+
+// ShouldDraw reports whether the node being rendered belongs to the layer
+// the current pass draws. Nodes still transform, and render their
+// children, when it is false.
+func (c *RenderContext) ShouldDraw() bool {
+	return !c.multiPass || c.state.layer == c.pass
+}
+
+// Restore pops what Save, or push, saved.
 func (c *RenderContext) Restore() {
-	t := c.contextState.Pop().(*AffineTransform)
-	c.context.SetWithAT(t) // Copy to current context
-	AffinePool.Push(t)
+	last := len(c.frames) - 1
+	f := &c.frames[last]
+
+	*c.context = f.transform
+	c.state = f.state
+	if f.ggPushed {
+		c.dc.Pop()
+	}
 
-	c.dc.Pop()
+	c.frames = c.frames[:last]
 }
 
 func (c *RenderContext) DrawPolygon(vertices []*Vector3, color color.RGBA) {
+	if c.state.opacity <= 0.0 {
+		return
+	}
+	color = c.ModulateColor(color)
+
 	// Transform geometry for rendering
 	for i, p := range vertices {
 		c.context.ApplyTo(p, c.tPoints[i])
@@ -84,3 +238,12 @@ func (c *RenderContext) DrawPolygon(vertices []*Vector3, color color.RGBA) {
 	c.dc.ClosePath()
 	c.dc.Fill()
 }
+
+// FillTarget blends color over the whole target, ignoring the transform.
+func (c *RenderContext) FillTarget(color color.RGBA) {
+	b := c.target.Bounds()
+
+	c.dc.SetColor(color)
+	c.dc.DrawRectangle(float64(b.Min.X), float64(b.Min.Y), float64(b.Dx()), float64(b.Dy()))
+	c.dc.Fill()
+}
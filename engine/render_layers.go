@@ -0,0 +1,113 @@
+package engine
+
+import "math"
+
+// RenderLayer orders drawing across the whole scene graph regardless of
+// tree structure. Every node in a lower layer is drawn before any node in
+// a higher layer. Within a layer nodes draw in tree order. Any value can
+// be used; the constants are conventions.
+type RenderLayer int
+
+const (
+	// LayerInherit places a node in its parent's layer. It is the default.
+	LayerInherit RenderLayer = math.MinInt32
+
+	// LayerBackground is drawn underneath the world
+	LayerBackground RenderLayer = -100
+	// LayerWorld is the layer of a root that doesn't set one
+	LayerWorld RenderLayer = 0
+	// LayerHUD is drawn over the world
+	LayerHUD RenderLayer = 100
+)
+
+// Like world transforms, layer lists are validated by a version that is
+// global across all nodes. Changing a node's layer or visibility, or adding
+// or removing a child anywhere, bumps it so every cached list is recollected
+// when next asked for.
+var layerVersions uint64 = 1
+
+func layersChanged() {
+	layerVersions++
+}
+
+type layerCache struct {
+	layers []RenderLayer
+	// Zero means never collected
+	version uint64
+}
+
+func (n *BaseNode) layerCache() *layerCache {
+	return &n.layers
+}
+
+// layersOf returns the layers root's visible subtree uses, lowest first,
+// collecting them only if something changed since the last call. Don't
+// modify it.
+func layersOf(root INode) []RenderLayer {
+	c := root.layerCache()
+	if c.version != layerVersions {
+		c.layers = collectLayers(root, LayerWorld, c.layers[:0])
+		c.version = layerVersions
+	}
+	return c.layers
+}
+
+// renderTree renders root once per layer in use, lowest first. With a
+// single layer, the common case, this is one ordinary render.
+func renderTree(context *RenderContext, root INode) {
+	layers := layersOf(root)
+
+	if len(layers) <= 1 {
+		context.beginPass(LayerWorld, false)
+		root.Render(context)
+		return
+	}
+
+	for _, layer := range layers {
+		context.beginPass(layer, true)
+		root.Render(context)
+	}
+	context.beginPass(LayerWorld, false)
+}
+
+// collectLayers adds the layers of n's visible subtree to layers, keeping
+// it sorted and free of duplicates.
+func collectLayers(n INode, inherited RenderLayer, layers []RenderLayer) []RenderLayer {
+	if !n.IsVisible() {
+		return layers
+	}
+
+	layer := effectiveLayer(n, inherited)
+	layers = addLayer(layers, layer)
+
+	if g, ok := n.(IGroupNode); ok {
+		for _, child := range g.Children() {
+			layers = collectLayers(child, layer, layers)
+		}
+	}
+
+	return layers
+}
+
+func effectiveLayer(n INode, inherited RenderLayer) RenderLayer {
+	if l := n.Layer(); l != LayerInherit {
+		return l
+	}
+	return inherited
+}
+
+func addLayer(layers []RenderLayer, layer RenderLayer) []RenderLayer {
+	i := 0
+	for i < len(layers) && layers[i] < layer {
+		i++
+	}
+	if i < len(layers) && layers[i] == layer {
+		return layers
+	}
+
+	layers = append(layers, 0)
+	copy(layers[i+1:], layers[i:])
+	layers[i] = layer
+
+	return layers
+}
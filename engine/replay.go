@@ -0,0 +1,437 @@
+package engine
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// Replay files are little endian binary:
+//   header: magic, version, tick rate, max catch up steps, max frame time,
+//           then the state recording started in: timestep accumulator,
+//           clock, mouse, held buttons and the count of held keys
+//   held keys: scancodes
+//   frames: elapsed time (ms), event count, events...
+// Each event is a type byte followed by its fields.
+
+const (
+	replayMagic   = "RGRP"
+	replayVersion = uint16(2)
+)
+
+// ErrNotAReplay is returned when a file doesn't start with the replay header.
+var ErrNotAReplay = errors.New("not a replay file")
+
+type replayHeader struct {
+	Version         uint16
+	TickRate        float64
+	MaxCatchUpSteps int32
+	MaxFrameTime    float64
+
+	// State when recording started so playback begins identically
+	Accumulator  float64
+	Paused       bool
+	PendingSteps int32
+	TimeScale    float64
+	MouseX       int32
+	MouseY       int32
+	Buttons      [maxMouseButtons]bool
+	HeldKeyCount uint16
+}
+
+type replayFrame struct {
+	elapsedTime float64
+	events      []IEvent
+}
+
+// Recorder writes each frame's elapsed time and input events so the frame
+// sequence can be replayed later.
+type Recorder struct {
+	w      *bufio.Writer
+	frames int
+}
+
+// NewRecorder writes a replay header, capturing the timestep configuration
+// and the timestep, clock and input state, and returns a recorder ready for
+// frames.
+func NewRecorder(w io.Writer, timestep *FixedTimestep, clock *Clock, input *Input) (*Recorder, error) {
+	r := new(Recorder)
+	r.w = bufio.NewWriter(w)
+
+	_, err := r.w.WriteString(replayMagic)
+	if err != nil {
+		return nil, err
+	}
+
+	header := replayHeader{
+		Version:         replayVersion,
+		TickRate:        timestep.TickRate,
+		MaxCatchUpSteps: int32(timestep.MaxCatchUpSteps),
+		MaxFrameTime:    timestep.MaxFrameTime,
+		Accumulator:     timestep.accumulator,
+		Paused:          clock.paused,
+		PendingSteps:    int32(clock.pendingSteps),
+		TimeScale:       clock.timeScale,
+		MouseX:          input.mx,
+		MouseY:          input.my,
+		Buttons:         input.buttons,
+	}
+
+	held := input.heldKeys()
+	header.HeldKeyCount = uint16(len(held))
+
+	err = binary.Write(r.w, binary.LittleEndian, &header)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sc := range held {
+		err = r.write(uint16(sc))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+// RecordFrame appends a frame.
+func (r *Recorder) RecordFrame(elapsedTime float64, events []IEvent) error {
+	err := r.write(elapsedTime, uint16(len(events)))
+	if err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		err = r.writeEvent(event)
+		if err != nil {
+			return err
+		}
+	}
+
+	r.frames++
+	return nil
+}
+
+// Frames returns how many frames have been recorded.
+func (r *Recorder) Frames() int {
+	return r.frames
+}
+
+// Flush writes any buffered frames.
+func (r *Recorder) Flush() error {
+	return r.w.Flush()
+}
+
+func (r *Recorder) write(values ...interface{}) error {
+	for _, value := range values {
+		err := binary.Write(r.w, binary.LittleEndian, value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Recorder) writeEvent(event IEvent) error {
+	err := r.write(uint8(event.Type()))
+	if err != nil {
+		return err
+	}
+
+	switch t := event.(type) {
+	case *KeyEvent:
+		return r.write(uint16(t.Scancode), int32(t.Keycode), uint16(t.Modifiers))
+	case *MouseButtonEvent:
+		return r.write(uint8(t.Button), t.X, t.Y, t.Clicks)
+	case *MouseMotionEvent:
+		return r.write(t.X, t.Y, t.XRel, t.YRel)
+	case *MouseWheelEvent:
+		return r.write(t.X, t.Y)
+	case *TextInputEvent:
+		return r.write(uint16(len(t.Text)), []byte(t.Text))
+	case *WindowEvent:
+		return r.write(uint8(t.Kind), t.Data1, t.Data2)
+	case *QuitEvent:
+		return nil
+	}
+
+	return fmt.Errorf("can't record event type %d", event.Type())
+}
+
+// Replay is a recorded sequence of frames.
+type Replay struct {
+	header replayHeader
+	// Keys held when recording started
+	held   []sdl.Scancode
+	frames []replayFrame
+
+	// Next frame to play
+	index int
+}
+
+// LoadReplay reads a replay file.
+func LoadReplay(path string) (*Replay, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ReadReplay(f)
+}
+
+// ReadReplay reads a replay written by a Recorder.
+func ReadReplay(r io.Reader) (*Replay, error) {
+	br := bufio.NewReader(r)
+
+	magic := make([]byte, len(replayMagic))
+	_, err := io.ReadFull(br, magic)
+	if err != nil || string(magic) != replayMagic {
+		return nil, ErrNotAReplay
+	}
+
+	rp := new(Replay)
+	err = binary.Read(br, binary.LittleEndian, &rp.header)
+	if err != nil {
+		return nil, err
+	}
+
+	if rp.header.Version != replayVersion {
+		return nil, fmt.Errorf("unsupported replay version %d", rp.header.Version)
+	}
+
+	for i := 0; i < int(rp.header.HeldKeyCount); i++ {
+		var sc uint16
+		err = binary.Read(br, binary.LittleEndian, &sc)
+		if err != nil {
+			return nil, err
+		}
+		rp.held = append(rp.held, sdl.Scancode(sc))
+	}
+
+	for {
+		var frame replayFrame
+		var count uint16
+
+		err = binary.Read(br, binary.LittleEndian, &frame.elapsedTime)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		err = binary.Read(br, binary.LittleEndian, &count)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := 0; i < int(count); i++ {
+			event, err := readEvent(br)
+			if err != nil {
+				return nil, err
+			}
+			frame.events = append(frame.events, event)
+		}
+
+		rp.frames = append(rp.frames, frame)
+	}
+
+	return rp, nil
+}
+
+func readEvent(r io.Reader) (IEvent, error) {
+	var eventType uint8
+	err := binary.Read(r, binary.LittleEndian, &eventType)
+	if err != nil {
+		return nil, err
+	}
+
+	read := func(values ...interface{}) error {
+		for _, value := range values {
+			err := binary.Read(r, binary.LittleEndian, value)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	switch EventType(eventType) {
+	case EventKeyDown, EventKeyUp, EventKeyRepeat:
+		var scancode, modifiers uint16
+		var keycode int32
+		err = read(&scancode, &keycode, &modifiers)
+		event := NewKeyEvent(EventType(eventType), sdl.Scancode(scancode), KeyModifier(modifiers))
+		event.Keycode = sdl.Keycode(keycode)
+		return event, err
+	case EventMouseButtonDown, EventMouseButtonUp:
+		var button uint8
+		var x, y int32
+		var clicks uint8
+		err = read(&button, &x, &y, &clicks)
+		event := NewMouseButtonEvent(EventType(eventType), MouseButton(button), x, y)
+		event.Clicks = clicks
+		return event, err
+	case EventMouseMotion:
+		var x, y, xRel, yRel int32
+		err = read(&x, &y, &xRel, &yRel)
+		return NewMouseMotionEvent(x, y, xRel, yRel), err
+	case EventMouseWheel:
+		var x, y int32
+		err = read(&x, &y)
+		return NewMouseWheelEvent(x, y), err
+	case EventTextInput:
+		var length uint16
+		err = read(&length)
+		if err != nil {
+			return nil, err
+		}
+		text := make([]byte, length)
+		_, err = io.ReadFull(r, text)
+		return NewTextInputEvent(string(text)), err
+	case EventWindow:
+		var kind uint8
+		var data1, data2 int32
+		err = read(&kind, &data1, &data2)
+		event := NewWindowEvent(WindowEventKind(kind))
+		event.Data1 = data1
+		event.Data2 = data2
+		return event, err
+	case EventQuit:
+		return NewQuitEvent(), nil
+	}
+
+	return nil, fmt.Errorf("unknown replay event type %d", eventType)
+}
+
+// Len returns the number of frames.
+func (rp *Replay) Len() int {
+	return len(rp.frames)
+}
+
+// Done is true once every frame has been played.
+func (rp *Replay) Done() bool {
+	return rp.index >= len(rp.frames)
+}
+
+// Rewind restarts the replay from the first frame.
+func (rp *Replay) Rewind() {
+	rp.index = 0
+}
+
+// next returns the next frame to play.
+func (rp *Replay) next() *replayFrame {
+	f := &rp.frames[rp.index]
+	rp.index++
+	return f
+}
+
+// ----------------------------------------------------------------
+// Engine integration
+// ----------------------------------------------------------------
+
+// StartRecording records every following frame's elapsed time and input
+// to w. Call StopRecording to flush.
+func (v *Engine) StartRecording(w io.Writer) error {
+	recorder, err := NewRecorder(w, v.timestep, v.clock, v.input)
+	if err != nil {
+		return err
+	}
+
+	v.recorder = recorder
+	return nil
+}
+
+// StopRecording flushes and detaches the recorder.
+func (v *Engine) StopRecording() error {
+	if v.recorder == nil {
+		return nil
+	}
+
+	err := v.recorder.Flush()
+	v.recorder = nil
+	return err
+}
+
+// IsRecording is true while frames are being recorded.
+func (v *Engine) IsRecording() bool {
+	return v.recorder != nil
+}
+
+// PlayReplay feeds the replay's frames into the loop in place of live
+// input and real time. The timestep, clock and held input are restored to
+// how they were when recording started. Live input is ignored, apart from
+// quit requests, until the replay is done. For identical results start
+// from the same scene graph state as the recording did.
+func (v *Engine) PlayReplay(replay *Replay) {
+	h := &replay.header
+
+	v.timestep.TickRate = h.TickRate
+	v.timestep.MaxCatchUpSteps = int(h.MaxCatchUpSteps)
+	v.timestep.MaxFrameTime = h.MaxFrameTime
+	v.timestep.Reset()
+	v.timestep.accumulator = h.Accumulator
+
+	v.clock.paused = h.Paused
+	v.clock.pendingSteps = int(h.PendingSteps)
+	v.clock.SetTimeScale(h.TimeScale)
+
+	v.input.restoreHeld(replay.held, h.Buttons, h.MouseX, h.MouseY)
+
+	v.replay = replay
+}
+
+// IsReplaying is true while a replay is driving the loop.
+func (v *Engine) IsReplaying() bool {
+	return v.replay != nil
+}
+
+// replayFrame substitutes the next recorded frame's events and elapsed
+// time for the live ones.
+func (v *Engine) replayFrame(elapsedTime float64) float64 {
+	if v.replay.Done() {
+		v.replay = nil
+		return elapsedTime
+	}
+
+	// Only honor live quit requests
+	live := v.events
+	v.events = v.events[:0]
+	for _, event := range live {
+		if event.Type() == EventQuit {
+			v.events = append(v.events, event)
+		}
+	}
+
+	// The stored events are dispatched again on every playback
+	frame := v.replay.next()
+	for _, event := range frame.events {
+		if e, ok := event.(interface{ reset() }); ok {
+			e.reset()
+		}
+		v.events = append(v.events, event)
+	}
+
+	if v.replay.Done() {
+		v.replay = nil
+	}
+
+	return frame.elapsedTime
+}
+
+// recordFrame records the frame's elapsed time and events.
+func (v *Engine) recordFrame(elapsedTime float64) {
+	err := v.recorder.RecordFrame(elapsedTime, v.events)
+	if err != nil {
+		log.Println("Recording stopped: ", err)
+		v.recorder = nil
+	}
+}
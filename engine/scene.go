@@ -0,0 +1,60 @@
+package engine
+
+// IScene is a screen of the game, for example, a menu or a level, with its
+// own scene graph. Scenes are run by the SceneManager.
+type IScene interface {
+	Name() string
+	Root() IGroupNode
+
+	// OnEnter is called when the scene becomes the running scene. With a
+	// transition this is at the start of the transition.
+	OnEnter()
+	// OnExit is called when the scene stops running, either removed or
+	// covered by a pushed scene. With a transition this is at the end of
+	// the transition.
+	OnExit()
+	// OnEnterTransitionFinish is called once the scene is fully shown.
+	OnEnterTransitionFinish()
+}
+
+// BaseScene is a scene with no-op lifecycle hooks. Embed it and override
+// the hooks that are needed.
+type BaseScene struct {
+	name string
+	root IGroupNode
+}
+
+// NewBaseScene creates a scene with an empty root group.
+func NewBaseScene(name string) *BaseScene {
+	s := new(BaseScene)
+	s.Initialize(name, nil)
+	return s
+}
+
+// Initialize sets the scene's name and root. If root is nil an empty
+// group is created.
+func (s *BaseScene) Initialize(name string, root IGroupNode) {
+	if root == nil {
+		root = NewGroupNode(nil, false)
+		root.SetName(name)
+	}
+	s.name = name
+	s.root = root
+}
+
+func (s *BaseScene) Name() string {
+	return s.name
+}
+
+func (s *BaseScene) Root() IGroupNode {
+	return s.root
+}
+
+func (s *BaseScene) OnEnter() {
+}
+
+func (s *BaseScene) OnExit() {
+}
+
+func (s *BaseScene) OnEnterTransitionFinish() {
+}
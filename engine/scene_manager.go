@@ -0,0 +1,173 @@
+package engine
+
+import "errors"
+
+// ErrNoScene is returned when popping the last scene.
+var ErrNoScene = errors.New("no scene to return to")
+
+// SceneManager runs a stack of scenes. The top scene is the running scene;
+// it is updated and rendered and receives pointer events. Changing scenes
+// can be animated with an ITransition, during which both the outgoing and
+// incoming scenes are rendered.
+type SceneManager struct {
+	stack []IScene
+
+	// Active transition, if any
+	transition ITransition
+	from       IScene
+	to         IScene
+	// Whether the outgoing scene exits at the end of the transition
+	exitFrom bool
+	elapsed  float64
+
+	// Tree that runs while the stack is empty, the engine's root
+	idle INode
+}
+
+// NewSceneManager creates an empty scene manager.
+func NewSceneManager() *SceneManager {
+	return new(SceneManager)
+}
+
+// Running returns the top scene or nil if there are none.
+func (m *SceneManager) Running() IScene {
+	if len(m.stack) == 0 {
+		return nil
+	}
+	return m.stack[len(m.stack)-1]
+}
+
+// Len returns the number of scenes on the stack.
+func (m *SceneManager) Len() int {
+	return len(m.stack)
+}
+
+// IsTransitioning is true while a transition is in progress.
+func (m *SceneManager) IsTransitioning() bool {
+	return m.transition != nil
+}
+
+// Push covers the running scene with scene. The covered scene exits and
+// enters again when scene is popped. transition may be nil.
+func (m *SceneManager) Push(scene IScene, transition ITransition) {
+	m.finishTransition()
+
+	from := m.Running()
+	m.stack = append(m.stack, scene)
+	m.change(from, scene, transition)
+}
+
+// Pop removes the running scene and returns to the one beneath it.
+func (m *SceneManager) Pop(transition ITransition) error {
+	m.finishTransition()
+
+	if len(m.stack) < 2 {
+		return ErrNoScene
+	}
+
+	from := m.Running()
+	m.stack[len(m.stack)-1] = nil
+	m.stack = m.stack[:len(m.stack)-1]
+	m.change(from, m.Running(), transition)
+
+	return nil
+}
+
+// Replace swaps the running scene for scene. On an empty stack this is
+// the same as Push.
+func (m *SceneManager) Replace(scene IScene, transition ITransition) {
+	m.finishTransition()
+
+	from := m.Running()
+	if from != nil {
+		m.stack[len(m.stack)-1] = scene
+	} else {
+		m.stack = append(m.stack, scene)
+	}
+	m.change(from, scene, transition)
+}
+
+func (m *SceneManager) change(from, to IScene, transition ITransition) {
+	// The first scene takes over from the idle tree
+	if from == nil && m.idle != nil {
+		exitTree(m.idle)
+	}
+
+	if transition == nil || transition.Duration() <= 0.0 {
+		if from != nil {
+			exitScene(from)
+		}
+		enterScene(to)
+		to.OnEnterTransitionFinish()
+		return
+	}
+
+	m.transition = transition
+	m.from = from
+	m.to = to
+	m.elapsed = 0.0
+
+	enterScene(to)
+}
+
+// finishTransition completes an active transition immediately.
+func (m *SceneManager) finishTransition() {
+	if m.transition == nil {
+		return
+	}
+
+	from, to := m.from, m.to
+	m.transition = nil
+	m.from = nil
+	m.to = nil
+
+	if from != nil {
+		exitScene(from)
+	}
+	to.OnEnterTransitionFinish()
+}
+
+// advance moves an active transition on by dt seconds.
+func (m *SceneManager) advance(dt float64) {
+	if m.transition == nil {
+		return
+	}
+
+	m.elapsed += dt
+	if m.elapsed >= m.transition.Duration() {
+		m.finishTransition()
+	}
+}
+
+// progress returns how far through the transition is, [0, 1].
+func (m *SceneManager) progress() float64 {
+	t := m.elapsed / m.transition.Duration()
+	if t > 1.0 {
+		t = 1.0
+	}
+	return t
+}
+
+// render draws the running scene or the active transition.
+func (m *SceneManager) render(context *RenderContext) {
+	if m.transition != nil {
+		m.transition.Render(context, m.from, m.to, m.progress())
+		return
+	}
+
+	if s := m.Running(); s != nil {
+		renderTree(context, s.Root())
+	}
+}
+
+// enterScene runs the scene's OnEnter and then enters its scene graph.
+func enterScene(s IScene) {
+	s.OnEnter()
+	enterTree(s.Root())
+}
+
+// exitScene exits the scene's scene graph and then runs its OnExit.
+func exitScene(s IScene) {
+	exitTree(s.Root())
+	s.OnExit()
+}
@@ -0,0 +1,383 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// IPropertied is implemented by nodes that have type specific state to
+// serialize, for example, a star's number of points. The properties are
+// handed back to the type's NodeFactory when the node is loaded.
+type IPropertied interface {
+	Properties() Properties
+}
+
+// Vector2Descriptor is the serialized form of a 2D vector.
+type Vector2Descriptor struct {
+	X float64 `json:"x" yaml:"x"`
+	Y float64 `json:"y" yaml:"y"`
+}
+
+// NodeDescriptor is the serialized form of a node and its subtree.
+type NodeDescriptor struct {
+	Type     string            `json:"type" yaml:"type"`
+	Name     string            `json:"name,omitempty" yaml:"name,omitempty"`
+	Tags     []string          `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Position Vector2Descriptor `json:"position" yaml:"position"`
+	// Scale defaults to 1,1 when missing
+	Scale *Vector2Descriptor `json:"scale,omitempty" yaml:"scale,omitempty"`
+	// Anchor and ContentSize are left as the node type creates them when
+	// missing
+	Anchor      *Vector2Descriptor `json:"anchor,omitempty" yaml:"anchor,omitempty"`
+	ContentSize *Vector2Descriptor `json:"contentSize,omitempty" yaml:"contentSize,omitempty"`
+	// Rotation is in degrees
+	Rotation float64 `json:"rotation" yaml:"rotation"`
+	// Visible defaults to true when missing
+	Visible *bool `json:"visible,omitempty" yaml:"visible,omitempty"`
+	// Color is "#rrggbbaa"
+	Color string `json:"color" yaml:"color"`
+	// Opacity is omitted when opaque and Tint when white
+	Opacity *float64 `json:"opacity,omitempty" yaml:"opacity,omitempty"`
+	Tint    string   `json:"tint,omitempty" yaml:"tint,omitempty"`
+	ZIndex  int      `json:"zIndex,omitempty" yaml:"zIndex,omitempty"`
+	// Layer is omitted when inherited
+	Layer *RenderLayer `json:"layer,omitempty" yaml:"layer,omitempty"`
+	// TimeScale is a group's, omitted when 1
+	TimeScale *float64 `json:"timeScale,omitempty" yaml:"timeScale,omitempty"`
+
+	Properties Properties        `json:"properties,omitempty" yaml:"properties,omitempty"`
+	Children   []*NodeDescriptor `json:"children,omitempty" yaml:"children,omitempty"`
+}
+
+// DescribeNode captures n and its subtree. Every node's type must be
+// registered with RegisterNodeType.
+func DescribeNode(n INode) (*NodeDescriptor, error) {
+	typeName, err := NodeTypeName(n)
+	if err != nil {
+		return nil, err
+	}
+
+	d := new(NodeDescriptor)
+	d.Type = typeName
+	d.Name = n.Name()
+	d.Tags = append([]string(nil), n.Tags()...)
+	d.Position = Vector2Descriptor{n.Position().X, n.Position().Y}
+	d.Scale = &Vector2Descriptor{n.Scale().X, n.Scale().Y}
+	d.Anchor = &Vector2Descriptor{n.Anchor().X, n.Anchor().Y}
+	d.ContentSize = &Vector2Descriptor{n.ContentSize().X, n.ContentSize().Y}
+	d.Rotation = n.Rotation() / DegreeToRadians
+	visible := n.IsVisible()
+	d.Visible = &visible
+	d.Color = FormatColor(n.Color())
+	if opacity := n.Opacity(); opacity != 1.0 {
+		d.Opacity = &opacity
+	}
+	if tint := n.Tint(); tint != (color.RGBA{255, 255, 255, 255}) {
+		d.Tint = FormatColor(tint)
+	}
+	d.ZIndex = n.ZIndex()
+	if layer := n.Layer(); layer != LayerInherit {
+		d.Layer = &layer
+	}
+
+	if p, ok := n.(IPropertied); ok {
+		d.Properties = p.Properties()
+	}
+
+	if g, ok := n.(IGroupNode); ok {
+		if scale := g.TimeScale(); scale != 1.0 {
+			d.TimeScale = &scale
+		}
+
+		for _, child := range g.Children() {
+			cd, err := DescribeNode(child)
+			if err != nil {
+				return nil, err
+			}
+			d.Children = append(d.Children, cd)
+		}
+	}
+
+	return d, nil
+}
+
+// commonProperties returns d's fields that every node has in the form
+// ApplyProperties takes, with the defaults for missing scale and
+// visibility filled in.
+func (d *NodeDescriptor) commonProperties() Properties {
+	props := Properties{
+		"name":     d.Name,
+		"x":        d.Position.X,
+		"y":        d.Position.Y,
+		"scaleX":   1.0,
+		"scaleY":   1.0,
+		"rotation": d.Rotation,
+		"visible":  d.Visible == nil || *d.Visible,
+		"zIndex":   d.ZIndex,
+	}
+
+	if len(d.Tags) > 0 {
+		props["tags"] = d.Tags
+	}
+	if d.Scale != nil {
+		props["scaleX"] = d.Scale.X
+		props["scaleY"] = d.Scale.Y
+	}
+	if d.ContentSize != nil {
+		props["width"] = d.ContentSize.X
+		props["height"] = d.ContentSize.Y
+	}
+	if d.Anchor != nil {
+		props["anchorX"] = d.Anchor.X
+		props["anchorY"] = d.Anchor.Y
+	}
+	if d.Color != "" {
+		props["color"] = d.Color
+	}
+	if d.Opacity != nil {
+		props["opacity"] = *d.Opacity
+	}
+	if d.Tint != "" {
+		props["tint"] = d.Tint
+	}
+	if d.Layer != nil {
+		props["layer"] = int(*d.Layer)
+	}
+
+	return props
+}
+
+// BuildNode creates the node and subtree described by d. If parent isn't
+// nil the node is added to it once the subtree is complete.
+func BuildNode(d *NodeDescriptor, parent IGroupNode) (INode, error) {
+	return buildNode(d, parent, nil)
+}
+
+// buildNode is BuildNode calling built, if not nil, with each node as
+// soon as it is created from its descriptor.
+func buildNode(d *NodeDescriptor, parent IGroupNode, built func(*NodeDescriptor, INode) error) (INode, error) {
+	n, err := newNode(d.Type, parent, d.Properties)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ApplyProperties(n, d.commonProperties())
+	if err != nil {
+		return nil, err
+	}
+
+	if d.TimeScale != nil {
+		g, ok := n.(IGroupNode)
+		if !ok {
+			return nil, fmt.Errorf("node type '%s' has no time scale", d.Type)
+		}
+		g.SetTimeScale(*d.TimeScale)
+	}
+
+	if built != nil {
+		err = built(d, n)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(d.Children) > 0 {
+		g, ok := n.(IGroupNode)
+		if !ok {
+			return nil, fmt.Errorf("node type '%s' can't have children", d.Type)
+		}
+		for _, cd := range d.Children {
+			_, err = buildNode(cd, g, built)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if parent != nil {
+		parent.Add(n)
+	}
+
+	return n, nil
+}
+
+// Copy returns a deep copy of d. Property values are shared.
+func (d *NodeDescriptor) Copy() *NodeDescriptor {
+	c := *d
+	c.Tags = append([]string(nil), d.Tags...)
+
+	if d.Scale != nil {
+		scale := *d.Scale
+		c.Scale = &scale
+	}
+	if d.Visible != nil {
+		visible := *d.Visible
+		c.Visible = &visible
+	}
+	if d.Anchor != nil {
+		anchor := *d.Anchor
+		c.Anchor = &anchor
+	}
+	if d.ContentSize != nil {
+		size := *d.ContentSize
+		c.ContentSize = &size
+	}
+
+	if d.Layer != nil {
+		layer := *d.Layer
+		c.Layer = &layer
+	}
+	if d.Opacity != nil {
+		opacity := *d.Opacity
+		c.Opacity = &opacity
+	}
+	if d.TimeScale != nil {
+		scale := *d.TimeScale
+		c.TimeScale = &scale
+	}
+
+	if d.Properties != nil {
+		c.Properties = Properties{}
+		for k, v := range d.Properties {
+			c.Properties[k] = v
+		}
+	}
+
+	c.Children = nil
+	for _, cd := range d.Children {
+		c.Children = append(c.Children, cd.Copy())
+	}
+
+	return &c
+}
+
+// Clone makes a deep copy of n and its subtree by describing and
+// rebuilding it. The copy has no parent. Only state that is serialized is
+// copied so, for example, pointer handlers aren't.
+func Clone(n INode) (INode, error) {
+	d, err := DescribeNode(n)
+	if err != nil {
+		return nil, err
+	}
+	return BuildNode(d, nil)
+}
+
+// MarshalSceneJSON serializes n and its subtree to JSON.
+func MarshalSceneJSON(n INode) ([]byte, error) {
+	d, err := DescribeNode(n)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(d, "", "  ")
+}
+
+// UnmarshalSceneJSON builds the scene in data and adds it to parent, if
+// not nil.
+func UnmarshalSceneJSON(data []byte, parent IGroupNode) (INode, error) {
+	d := new(NodeDescriptor)
+	err := json.Unmarshal(data, d)
+	if err != nil {
+		return nil, err
+	}
+	return BuildNode(d, parent)
+}
+
+// MarshalSceneYAML serializes n and its subtree to YAML.
+func MarshalSceneYAML(n INode) ([]byte, error) {
+	d, err := DescribeNode(n)
+	if err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(d)
+}
+
+// UnmarshalSceneYAML builds the scene in data and adds it to parent, if
+// not nil.
+func UnmarshalSceneYAML(data []byte, parent IGroupNode) (INode, error) {
+	d := new(NodeDescriptor)
+	err := yaml.Unmarshal(data, d)
+	if err != nil {
+		return nil, err
+	}
+	return BuildNode(d, parent)
+}
+
+// SaveScene writes n and its subtree to a ".json", ".yaml" or ".yml" file.
+func SaveScene(path string, n INode) error {
+	var data []byte
+	var err error
+
+	if isYAMLPath(path) {
+		data, err = MarshalSceneYAML(n)
+	} else {
+		data, err = MarshalSceneJSON(n)
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
+
+// LoadScene reads a ".json", ".yaml" or ".yml" scene file and adds the
+// scene to parent, if not nil.
+func LoadScene(path string, parent IGroupNode) (INode, error) {
+	d, err := LoadSceneDescriptor(path)
+	if err != nil {
+		return nil, err
+	}
+	return BuildNode(d, parent)
+}
+
+// LoadSceneDescriptor reads a scene file without building it.
+func LoadSceneDescriptor(path string) (*NodeDescriptor, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	d := new(NodeDescriptor)
+	if isYAMLPath(path) {
+		err = yaml.Unmarshal(data, d)
+	} else {
+		err = json.Unmarshal(data, d)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+func isYAMLPath(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// FormatColor formats a color as "#rrggbbaa".
+func FormatColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x%02x", c.R, c.G, c.B, c.A)
+}
+
+// ParseColor parses "#rrggbbaa" or "#rrggbb", which is opaque.
+func ParseColor(s string) (color.RGBA, error) {
+	c := color.RGBA{A: 255}
+	var err error
+
+	switch len(s) {
+	case 9:
+		_, err = fmt.Sscanf(s, "#%02x%02x%02x%02x", &c.R, &c.G, &c.B, &c.A)
+	case 7:
+		_, err = fmt.Sscanf(s, "#%02x%02x%02x", &c.R, &c.G, &c.B)
+	default:
+		err = fmt.Errorf("invalid color '%s'", s)
+	}
+
+	return c, err
+}
@@ -0,0 +1,116 @@
+package engine
+
+// RepeatForever makes ScheduleInterval repeat until cancelled.
+const RepeatForever = -1
+
+// TimerCallback is called with the scene time, in seconds, since the
+// timer last fired or was scheduled.
+type TimerCallback func(dt float64)
+
+// Timer is a callback scheduled on a node. Timers advance with the node's
+// Update so they stop while the clock is paused and follow the clock's
+// and any group's time scale. They are cancelled when the node leaves the
+// running scene, so schedule them in OnEnter.
+type Timer struct {
+	callback TimerCallback
+
+	// Zero fires every update
+	interval float64
+	// Calls left, or RepeatForever
+	repeats int
+
+	elapsed   float64
+	cancelled bool
+}
+
+// Cancel stops the timer. It is safe to call from the timer's callback.
+func (t *Timer) Cancel() {
+	t.cancelled = true
+}
+
+// IsActive is true until the timer is cancelled or has fired its last
+// time.
+func (t *Timer) IsActive() bool {
+	return !t.cancelled
+}
+
+// advance moves the timer on, firing it as often as dt covers.
+func (t *Timer) advance(dt float64) {
+	if t.interval <= 0.0 {
+		t.callback(dt)
+		return
+	}
+
+	t.elapsed += dt
+	for !t.cancelled && t.elapsed >= t.interval {
+		t.elapsed -= t.interval
+		t.callback(t.interval)
+
+		if t.repeats != RepeatForever {
+			t.repeats--
+			if t.repeats <= 0 {
+				t.cancelled = true
+			}
+		}
+	}
+}
+
+// Schedule calls callback every update with the update's dt.
+func (n *BaseNode) Schedule(callback TimerCallback) *Timer {
+	return n.addTimer(&Timer{callback: callback, repeats: RepeatForever})
+}
+
+// ScheduleOnce calls callback once after delay seconds.
+func (n *BaseNode) ScheduleOnce(delay float64, callback func()) *Timer {
+	return n.addTimer(&Timer{
+		callback: func(float64) { callback() },
+		interval: delay,
+		repeats:  1,
+	})
+}
+
+// ScheduleInterval calls callback every interval seconds, repeats times
+// or RepeatForever. If an update spans several intervals the callback is
+// called for each.
+func (n *BaseNode) ScheduleInterval(interval float64, repeats int, callback TimerCallback) *Timer {
+	return n.addTimer(&Timer{callback: callback, interval: interval, repeats: repeats})
+}
+
+// UnscheduleAll cancels every timer on the node.
+func (n *BaseNode) UnscheduleAll() {
+	for _, t := range n.timers {
+		t.cancelled = true
+	}
+	n.timers = nil
+}
+
+func (n *BaseNode) addTimer(t *Timer) *Timer {
+	n.timers = append(n.timers, t)
+	return t
+}
+
+// tickTimers advances the node's timers by dt.
+func (n *BaseNode) tickTimers(dt float64) {
+	if len(n.timers) == 0 {
+		return
+	}
+
+	// Timers scheduled by a callback start on the next update. A callback
+	// can also remove the node, unscheduling everything.
+	for _, t := range n.timers {
+		if !t.cancelled {
+			t.advance(dt)
+		}
+	}
+
+	active := n.timers[:0]
+	for _, t := range n.timers {
+		if !t.cancelled {
+			active = append(active, t)
+		}
+	}
+	for i := len(active); i < len(n.timers); i++ {
+		n.timers[i] = nil
+	}
+	n.timers = active
+}
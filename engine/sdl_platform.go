@@ -0,0 +1,259 @@
+package engine
+
+import (
+	"fmt"
+	"image"
+	"log"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// SDLPlatform renders the engine's drawing buffer into an SDL window.
+type SDLPlatform struct {
+	window   *sdl.Window
+	surface  *sdl.Surface
+	renderer *sdl.Renderer
+	texture  *sdl.Texture
+
+	vsync bool
+
+	txtSimStatus *Text
+	txtFPSLabel  *Text
+	txtLoopLabel *Text
+	txtMousePos  *Text
+	dynaTxt      *DynaText
+}
+
+// NewSDLPlatform creates a platform backed by an SDL window.
+func NewSDLPlatform() *SDLPlatform {
+	return new(SDLPlatform)
+}
+
+// Initialize opens the window and creates the renderer and streaming texture.
+func (p *SDLPlatform) Initialize(title string, width, height int32) error {
+	var err error
+
+	err = sdl.Init(sdl.INIT_TIMER | sdl.INIT_VIDEO | sdl.INIT_EVENTS)
+	if err != nil {
+		return err
+	}
+
+	p.window, err = sdl.CreateWindow(title, 100, 100,
+		width, height, sdl.WINDOW_SHOWN)
+
+	if err != nil {
+		return err
+	}
+
+	// Using GetSurface requires using window.UpdateSurface() rather than renderer.Present.
+	// p.surface, err = p.window.GetSurface()
+	// if err != nil {
+	// 	return err
+	// }
+	// p.renderer, err = sdl.CreateSoftwareRenderer(p.surface)
+	// OR create renderer manually
+	// The software renderer can't sync to the display so vsync requires
+	// an accelerated renderer.
+	var flags uint32 = sdl.RENDERER_SOFTWARE
+	if p.vsync {
+		flags = sdl.RENDERER_ACCELERATED | sdl.RENDERER_PRESENTVSYNC
+	}
+
+	p.renderer, err = sdl.CreateRenderer(p.window, -1, flags)
+	if err != nil {
+		return err
+	}
+
+	p.texture, err = p.renderer.CreateTexture(sdl.PIXELFORMAT_ABGR8888, sdl.TEXTUREACCESS_STREAMING, width, height)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SetVSync selects a vsync'ed renderer. It must be called before Initialize.
+func (p *SDLPlatform) SetVSync(enabled bool) error {
+	if p.renderer != nil && enabled != p.vsync {
+		return ErrVSyncAfterInitialize
+	}
+
+	p.vsync = enabled
+	return nil
+}
+
+// Configure creates the overlay text.
+func (p *SDLPlatform) Configure(font *Font) error {
+	// rect := sdl.Rect{X: 0, Y: 0, W: 200, H: 200}
+	// p.renderer.SetDrawColor(255, 127, 0, 255)
+	// p.renderer.FillRect(&rect)
+
+	p.txtSimStatus = NewText(font, p.renderer)
+	err := p.txtSimStatus.SetText("Sim Status: ", sdl.Color{R: 0, G: 0, B: 255, A: 255})
+	if err != nil {
+		return err
+	}
+
+	p.txtFPSLabel = NewText(font, p.renderer)
+	err = p.txtFPSLabel.SetText("FPS: ", sdl.Color{R: 200, G: 200, B: 200, A: 255})
+	if err != nil {
+		return err
+	}
+
+	p.txtMousePos = NewText(font, p.renderer)
+	err = p.txtMousePos.SetText("Mouse: ", sdl.Color{R: 255, G: 127, B: 0, A: 255})
+	if err != nil {
+		return err
+	}
+
+	p.txtLoopLabel = NewText(font, p.renderer)
+	err = p.txtLoopLabel.SetText("Loop: ", sdl.Color{R: 255, G: 127, B: 0, A: 255})
+	if err != nil {
+		return err
+	}
+
+	p.dynaTxt = NewDynaText(font, p.renderer, sdl.Color{R: 255, G: 255, B: 255, A: 255})
+
+	return nil
+}
+
+// PumpEvents drains SDL's event queue translating each event into a typed
+// engine event.
+func (p *SDLPlatform) PumpEvents(engine *Engine) {
+	for e := sdl.PollEvent(); e != nil; e = sdl.PollEvent() {
+		if event := translateEvent(e); event != nil {
+			engine.postEvent(event)
+		}
+	}
+}
+
+// translateEvent converts an SDL event. Unsupported events return nil.
+func translateEvent(e sdl.Event) IEvent {
+	switch t := e.(type) {
+	case *sdl.QuitEvent:
+		return NewQuitEvent()
+	case *sdl.KeyboardEvent:
+		eventType := EventKeyUp
+		if t.State == sdl.PRESSED {
+			eventType = EventKeyDown
+			if t.Repeat != 0 {
+				eventType = EventKeyRepeat
+			}
+		}
+		event := NewKeyEvent(eventType, t.Keysym.Scancode, modifiersFromSDL(t.Keysym.Mod))
+		event.Keycode = t.Keysym.Sym
+		return event
+	case *sdl.MouseButtonEvent:
+		eventType := EventMouseButtonUp
+		if t.State == sdl.PRESSED {
+			eventType = EventMouseButtonDown
+		}
+		event := NewMouseButtonEvent(eventType, MouseButton(t.Button), t.X, t.Y)
+		event.Clicks = t.Clicks
+		return event
+	case *sdl.MouseMotionEvent:
+		return NewMouseMotionEvent(t.X, t.Y, t.XRel, t.YRel)
+	case *sdl.MouseWheelEvent:
+		return NewMouseWheelEvent(t.X, t.Y)
+	case *sdl.TextInputEvent:
+		return NewTextInputEvent(t.GetText())
+	case *sdl.WindowEvent:
+		var kind WindowEventKind
+		switch t.Event {
+		case sdl.WINDOWEVENT_SHOWN:
+			kind = WindowShown
+		case sdl.WINDOWEVENT_HIDDEN:
+			kind = WindowHidden
+		case sdl.WINDOWEVENT_RESIZED:
+			kind = WindowResized
+		case sdl.WINDOWEVENT_MINIMIZED:
+			kind = WindowMinimized
+		case sdl.WINDOWEVENT_RESTORED:
+			kind = WindowRestored
+		case sdl.WINDOWEVENT_ENTER:
+			kind = WindowMouseEnter
+		case sdl.WINDOWEVENT_LEAVE:
+			kind = WindowMouseLeave
+		case sdl.WINDOWEVENT_FOCUS_GAINED:
+			kind = WindowFocusGained
+		case sdl.WINDOWEVENT_FOCUS_LOST:
+			kind = WindowFocusLost
+		case sdl.WINDOWEVENT_CLOSE:
+			kind = WindowClose
+		default:
+			return nil
+		}
+		event := NewWindowEvent(kind)
+		event.Data1 = t.Data1
+		event.Data2 = t.Data2
+		return event
+	}
+
+	return nil
+}
+
+// Present copies the drawing buffer to the window along with the overlay.
+func (p *SDLPlatform) Present(pixels *image.RGBA, stats *FrameStats) {
+	// p.texture.Update(nil, p.pixels, p.pixelPitch)
+	// This takes on average 5-7ms
+	p.texture.Update(nil, pixels.Pix, pixels.Stride)
+	p.renderer.Copy(p.texture, nil, nil)
+
+	if p.dynaTxt != nil {
+		p.renderRawOverlay(stats)
+	}
+
+	p.renderer.Present()
+}
+
+func (p *SDLPlatform) renderRawOverlay(stats *FrameStats) {
+	p.txtFPSLabel.DrawAt(10, 10)
+	f := fmt.Sprintf("%2.2f", 1.0/stats.ElapsedTime*1000.0)
+	p.dynaTxt.DrawAt(p.txtFPSLabel.Bounds.W+10, 10, f)
+
+	p.txtMousePos.DrawAt(10, 25)
+	f = fmt.Sprintf("<%d, %d>", stats.MouseX, stats.MouseY)
+	p.dynaTxt.DrawAt(p.txtMousePos.Bounds.W+10, 25, f)
+
+	p.txtLoopLabel.DrawAt(10, 40)
+	f = fmt.Sprintf("%2.2f", stats.LoopTime)
+	p.dynaTxt.DrawAt(p.txtLoopLabel.Bounds.W+10, 40, f)
+}
+
+// Delay sleeps using SDL's timer.
+func (p *SDLPlatform) Delay(ms uint32) {
+	sdl.Delay(ms)
+}
+
+// Close destroys the overlay, texture, renderer and window.
+func (p *SDLPlatform) Close() {
+	var err error
+
+	if p.dynaTxt != nil {
+		p.txtSimStatus.Destroy()
+		p.txtFPSLabel.Destroy()
+		p.txtMousePos.Destroy()
+		p.txtLoopLabel.Destroy()
+		p.dynaTxt.Destroy()
+	}
+
+	log.Println("Destroying texture")
+	err = p.texture.Destroy()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	log.Println("Destroying renderer")
+	err = p.renderer.Destroy()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	log.Println("Destroying window")
+	err = p.window.Destroy()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	sdl.Quit()
+}
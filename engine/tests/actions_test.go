@@ -0,0 +1,133 @@
+package tests
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/wdevore/GameEngine/engine"
+)
+
+func Test_ActionSequenceAndSpawn(t *testing.T) {
+	e, _ := newHeadlessEngine()
+	defer e.Close()
+
+	n := engine.NewRectangleNode(e.GetRoot(), false, true)
+
+	called := 0
+	r := e.Actions().Run(n, engine.NewSequence(
+		engine.NewMoveTo(0.5, 100.0, 50.0),
+		engine.NewSpawn(
+			engine.NewRotateBy(0.25, 90.0),
+			engine.NewFadeTo(0.5, 0.0),
+		),
+		engine.NewCallFunc(func(target engine.INode) { called++ }),
+	), "")
+
+	e.RunFrames(15)
+	if !near(n.Position().X, 50.0) || !near(n.Position().Y, 25.0) {
+		t.Errorf("Expected half way at 50,25, got %f,%f", n.Position().X, n.Position().Y)
+	}
+
+	e.RunFrames(60)
+	if !near(n.Position().X, 100.0) || !near(n.Rotation(), 90.0*engine.DegreeToRadians) {
+		t.Errorf("Expected move and rotate to finish, got %f, %f", n.Position().X, n.Rotation())
+	}
+	if n.Opacity() != 0.0 || called != 1 {
+		t.Errorf("Expected faded out and one call, got %f, %d", n.Opacity(), called)
+	}
+	if !r.IsDone() || e.Actions().Count() != 0 {
+		t.Errorf("Expected the sequence to be done")
+	}
+}
+
+func Test_ActionRepeatAndEase(t *testing.T) {
+	e, _ := newHeadlessEngine()
+	defer e.Close()
+
+	n := engine.NewRectangleNode(e.GetRoot(), false, true)
+
+	e.Actions().Run(n, engine.NewRepeat(engine.NewMoveBy(0.25, 10.0, 0.0), 3), "")
+	e.RunFrames(60)
+	if !near(n.Position().X, 30.0) {
+		t.Errorf("Expected 3 moves of 10, got %f", n.Position().X)
+	}
+
+	// Ease in quadratically, half way through a quarter of the change is made
+	quad := func(t, b, c, d float64) float64 {
+		t /= d
+		return c*t*t + b
+	}
+	m := engine.NewRectangleNode(e.GetRoot(), false, true)
+	e.Actions().Run(m, engine.NewEase(engine.NewTintTo(1.0, color.RGBA{0, 0, 0, 255}), quad), "")
+	e.RunFrames(30)
+	if m.Tint().R != 191 {
+		t.Errorf("Expected a quarter tinted, got %v", m.Tint())
+	}
+}
+
+func Test_ActionsByTag(t *testing.T) {
+	e, _ := newHeadlessEngine()
+	defer e.Close()
+
+	a := engine.NewRectangleNode(e.GetRoot(), false, true)
+	b := engine.NewRectangleNode(e.GetRoot(), false, true)
+
+	actions := e.Actions()
+	actions.Run(a, engine.NewForever(engine.NewRotateBy(1.0, 90.0)), "spin")
+	actions.Run(b, engine.NewForever(engine.NewRotateBy(1.0, 90.0)), "spin")
+	mover := actions.Run(a, engine.NewMoveBy(10.0, 100.0, 0.0), "move")
+
+	actions.SetSpeed("spin", 2.0)
+	e.RunFrames(60)
+	if !near(a.Rotation(), 180.0*engine.DegreeToRadians) || !near(a.Position().X, 10.0) {
+		t.Errorf("Expected double speed spin, got %f, %f", a.Rotation(), a.Position().X)
+	}
+
+	actions.Pause("spin")
+	e.RunFrames(60)
+	if !near(b.Rotation(), 180.0*engine.DegreeToRadians) || !near(a.Position().X, 20.0) {
+		t.Errorf("Expected only the spin paused, got %f, %f", b.Rotation(), a.Position().X)
+	}
+
+	actions.Cancel("spin")
+	if len(actions.Running("spin")) != 0 || actions.Count() != 1 {
+		t.Errorf("Expected only the move to remain, got %d", actions.Count())
+	}
+
+	// Leaving the scene cancels a node's actions
+	e.GetRoot().Remove(a)
+	if !mover.IsDone() || actions.Count() != 0 {
+		t.Errorf("Expected the move cancelled on exit")
+	}
+}
+
+func Test_ActionRepeatInstant(t *testing.T) {
+	e, _ := newHeadlessEngine()
+	defer e.Close()
+
+	n := engine.NewRectangleNode(e.GetRoot(), false, true)
+
+	calls := 0
+	count := func(engine.INode) { calls++ }
+
+	r := e.Actions().Run(n, engine.NewRepeat(engine.NewCallFunc(count), 3), "")
+	e.RunFrames(1)
+	if calls != 3 || !r.IsDone() {
+		t.Errorf("Expected 3 calls in one update, got %d", calls)
+	}
+
+	// Within a sequence, and forever once per update
+	calls = 0
+	e.Actions().Run(n, engine.NewSequence(engine.NewDelay(0.1), engine.NewRepeat(engine.NewCallFunc(count), 2)), "")
+	e.RunFrames(30)
+	if calls != 2 {
+		t.Errorf("Expected 2 calls after the delay, got %d", calls)
+	}
+
+	calls = 0
+	e.Actions().Run(n, engine.NewForever(engine.NewCallFunc(count)), "")
+	e.RunFrames(10)
+	if calls != 10 {
+		t.Errorf("Expected a call per update, got %d", calls)
+	}
+}
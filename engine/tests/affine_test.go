@@ -12,7 +12,7 @@ func Test_Translate(t *testing.T) {
 	at := engine.NewAffineTransform()
 	t.Logf("v: %s\n", v)
 	at.SetToTranslate(2.0, 0.0)
-	at.ApplyToVector(v)
+	at.ApplyTo(v, v)
 	t.Logf("v: %s\n", v)
 	if v.X != 2.0 {
 		t.Error("Expected v.X == 2")
@@ -28,7 +28,7 @@ func Test_Rotate(t *testing.T) {
 	t.Logf("Angle: %f\n", angle)
 	at.SetToRotate(angle)
 	t.Logf("at: \n%s\n", at)
-	at.ApplyToVector(v)
+	at.ApplyToVector(v, v)
 	t.Logf("v: %s\n", v)
 
 	// Assuming +Y is downward then:
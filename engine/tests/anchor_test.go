@@ -0,0 +1,85 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/wdevore/GameEngine/engine"
+)
+
+func Test_AnchorPivot(t *testing.T) {
+	n := engine.NewRectangleNode(nil, false, false)
+	n.SetContentSize(20, 10)
+	n.SetPositionBy2Comp(100, 100)
+
+	out := engine.NewVector3()
+
+	// Anchored at the origin the top-left corner is at the position
+	n.NodeToWorld(engine.NewVector3With2Components(0, 0), out)
+	if !nearVector(out, 100, 100) {
+		t.Errorf("Expected <100, 100>, got %v", out)
+	}
+
+	// Anchored at the center the center is at the position...
+	n.SetAnchor(0.5, 0.5)
+	n.NodeToWorld(engine.NewVector3With2Components(10, 5), out)
+	if !nearVector(out, 100, 100) {
+		t.Errorf("Expected center at <100, 100>, got %v", out)
+	}
+
+	// ...and it rotates and scales about the center
+	n.SetRotationByDegree(90)
+	n.SetScaleUniform(2)
+	n.NodeToWorld(engine.NewVector3With2Components(10, 5), out)
+	if !nearVector(out, 100, 100) {
+		t.Errorf("Expected center to stay at <100, 100>, got %v", out)
+	}
+	n.NodeToWorld(engine.NewVector3With2Components(20, 5), out)
+	if !nearVector(out, 100, 120) {
+		t.Errorf("Expected right edge rotated below the center, got %v", out)
+	}
+
+	// Anchors are normalized so the pivot follows the size
+	n.SetContentSize(40, 10)
+	n.NodeToWorld(engine.NewVector3With2Components(20, 5), out)
+	if !nearVector(out, 100, 100) {
+		t.Errorf("Expected the new center at <100, 100>, got %v", out)
+	}
+}
+
+func Test_RectangleCentered(t *testing.T) {
+	e, _ := newHeadlessEngine()
+	defer e.Close()
+
+	// A centered rectangle's local origin is its center, as it always was
+	rect := engine.NewRectangleNode(e.GetRoot(), true, true)
+	if a := rect.Anchor(); a.X != 0.0 || a.Y != 0.0 {
+		t.Errorf("Expected the default anchor, got %v", a)
+	}
+
+	rect.SetPositionBy2Comp(32, 32)
+	rect.SetContentSize(20, 10)
+
+	out := engine.NewVector3()
+	rect.NodeToWorld(engine.NewVector3(), out)
+	if !nearVector(out, 32, 32) {
+		t.Errorf("Expected the local origin at the center, got %v", out)
+	}
+
+	e.RunFrames(1)
+
+	if c := e.Pixels().RGBAAt(24, 30); c != rect.Color() {
+		t.Errorf("Expected rectangle inside its centered bounds, got %v", c)
+	}
+	if c := e.Pixels().RGBAAt(32, 40); c != e.ClearColor {
+		t.Errorf("Expected background outside its centered bounds, got %v", c)
+	}
+
+	if hit := engine.HitTest(e.GetRoot(), 41, 36); hit != rect {
+		t.Errorf("Expected to hit the rectangle, got %v", hit)
+	}
+
+	rect.(*engine.RectangleNode).SetCentered(false)
+	if hit := engine.HitTest(e.GetRoot(), 30, 30); hit != nil {
+		t.Errorf("Expected a miss above-left of the corner, got %v", hit)
+	}
+}
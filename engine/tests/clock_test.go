@@ -0,0 +1,81 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/wdevore/GameEngine/engine"
+)
+
+// updateCounter accumulates the dt it is updated with.
+type updateCounter struct {
+	engine.INode
+	time float64
+}
+
+func (n *updateCounter) Update(dt float64) {
+	n.time += dt
+}
+
+func newUpdateCounter(parent engine.IGroupNode) *updateCounter {
+	n := new(updateCounter)
+	n.INode = engine.NewRectangleNode(parent, false, false)
+	parent.Add(n)
+	return n
+}
+
+func Test_ClockPauseAndStep(t *testing.T) {
+	e, _ := newHeadlessEngine()
+	defer e.Close()
+
+	counter := newUpdateCounter(e.GetRoot())
+	game := new(recordingGame)
+	e.SetGame(game)
+
+	clock := e.Clock()
+	clock.Pause()
+	e.RunFrames(5)
+
+	if counter.time != 0.0 {
+		t.Errorf("Expected no scene updates while paused, got %f", counter.time)
+	}
+	if game.updates != 5 || game.dt != 0.0 {
+		t.Errorf("Expected 5 game updates with dt 0, got %d with %f", game.updates, game.dt)
+	}
+
+	clock.Step(2)
+	e.RunFrames(5)
+
+	period := e.Timestep().StepPeriod()
+	if counter.time != 2*period {
+		t.Errorf("Expected 2 steps %f, got %f", 2*period, counter.time)
+	}
+	if !clock.IsPaused() || clock.Ticks() != 2 {
+		t.Errorf("Expected paused after 2 ticks, got paused=%v ticks=%d", clock.IsPaused(), clock.Ticks())
+	}
+}
+
+func Test_ClockTimeScale(t *testing.T) {
+	e, _ := newHeadlessEngine()
+	defer e.Close()
+
+	root := e.GetRoot()
+	normal := newUpdateCounter(root)
+
+	slowGroup := engine.NewGroupNode(root, true)
+	slowGroup.SetTimeScale(0.5)
+	slow := newUpdateCounter(slowGroup)
+
+	e.Clock().SetTimeScale(2.0)
+	e.RunFrames(4)
+
+	period := e.Timestep().StepPeriod()
+	if normal.time != 8*period {
+		t.Errorf("Expected %f, got %f", 8*period, normal.time)
+	}
+	if slow.time != 4*period {
+		t.Errorf("Expected %f, got %f", 4*period, slow.time)
+	}
+	if e.Clock().Time() != 8*period {
+		t.Errorf("Expected clock time %f, got %f", 8*period, e.Clock().Time())
+	}
+}
@@ -0,0 +1,136 @@
+package tests
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/wdevore/GameEngine/engine"
+)
+
+func Test_EasingEndpoints(t *testing.T) {
+	easings := map[string]engine.EasingFunction{
+		"Linear":         engine.LinearEasing,
+		"InQuad":         engine.EaseInQuad,
+		"OutQuad":        engine.EaseOutQuad,
+		"InOutQuad":      engine.EaseInOutQuad,
+		"InCubic":        engine.EaseInCubic,
+		"OutCubic":       engine.EaseOutCubic,
+		"InOutCubic":     engine.EaseInOutCubic,
+		"InQuart":        engine.EaseInQuart,
+		"OutQuart":       engine.EaseOutQuart,
+		"InOutQuart":     engine.EaseInOutQuart,
+		"InQuint":        engine.EaseInQuint,
+		"OutQuint":       engine.EaseOutQuint,
+		"InOutQuint":     engine.EaseInOutQuint,
+		"InSine":         engine.EaseInSine,
+		"OutSine":        engine.EaseOutSine,
+		"InOutSine":      engine.EaseInOutSine,
+		"InExpo":         engine.EaseInExpo,
+		"OutExpo":        engine.EaseOutExpo,
+		"InOutExpo":      engine.EaseInOutExpo,
+		"InCirc":         engine.EaseInCirc,
+		"OutCirc":        engine.EaseOutCirc,
+		"InOutCirc":      engine.EaseInOutCirc,
+		"InBack":         engine.EaseInBack,
+		"OutBack":        engine.EaseOutBack,
+		"InOutBack":      engine.EaseInOutBack,
+		"InElastic":      engine.EaseInElastic,
+		"OutElastic":     engine.EaseOutElastic,
+		"InOutElastic":   engine.EaseInOutElastic,
+		"InBounce":       engine.EaseInBounce,
+		"OutBounce":      engine.EaseOutBounce,
+		"InOutBounce":    engine.EaseInOutBounce,
+		"CubicBezier":    engine.NewCubicBezierEasing(0.25, 0.1, 0.25, 1.0),
+		"CubicBezierOut": engine.NewCubicBezierEasing(0.0, 0.0, 0.58, 1.0),
+	}
+
+	for name, easing := range easings {
+		if v := easing(0.0, 10.0, 20.0, 2.0); !near(v, 10.0) {
+			t.Errorf("%s: expected start 10, got %f", name, v)
+		}
+		if v := easing(2.0, 10.0, 20.0, 2.0); !near(v, 30.0) {
+			t.Errorf("%s: expected end 30, got %f", name, v)
+		}
+	}
+}
+
+func Test_EasingShapes(t *testing.T) {
+	if v := engine.EaseInQuad(0.5, 0.0, 1.0, 1.0); !near(v, 0.25) {
+		t.Errorf("Expected in quad 0.25 halfway, got %f", v)
+	}
+	if v := engine.EaseInOutCubic(0.5, 0.0, 1.0, 1.0); !near(v, 0.5) {
+		t.Errorf("Expected in-out cubic 0.5 halfway, got %f", v)
+	}
+	if v := engine.EaseOutBack(0.8, 0.0, 1.0, 1.0); v <= 1.0 {
+		t.Errorf("Expected out back to overshoot, got %f", v)
+	}
+
+	// Control points on the diagonal are linear
+	linear := engine.NewCubicBezierEasing(1.0/3.0, 1.0/3.0, 2.0/3.0, 2.0/3.0)
+	for _, x := range []float64{0.1, 0.37, 0.5, 0.9} {
+		if v := linear(x, 0.0, 1.0, 1.0); !near(v, x) {
+			t.Errorf("Expected linear bezier %f, got %f", x, v)
+		}
+	}
+
+	// CSS ease-in matches in quad roughly
+	easeIn := engine.NewCubicBezierEasing(0.42, 0.0, 1.0, 1.0)
+	if v := easeIn(0.5, 0.0, 1.0, 1.0); v < 0.3 || v > 0.33 {
+		t.Errorf("Expected ease-in near 0.315 halfway, got %f", v)
+	}
+}
+
+func Test_ParticleEasing(t *testing.T) {
+	p := engine.NewParticle()
+	p.IsAlive = true
+	p.StartColor = color.RGBA{0, 0, 0, 255}
+	p.EndColor = color.RGBA{200, 100, 255, 255}
+	p.Easing = engine.EaseInQuad
+
+	p.Update(0.5)
+	p.Update(0.5)
+	if p.RenderColor != (color.RGBA{50, 25, 63, 255}) {
+		t.Errorf("Expected a quarter of the way, got %v", p.RenderColor)
+	}
+
+	// Overshooting easings are clamped
+	p = engine.NewParticle()
+	p.IsAlive = true
+	p.StartColor = color.RGBA{0, 0, 0, 255}
+	p.EndColor = color.RGBA{255, 255, 255, 255}
+	p.Easing = engine.EaseOutBack
+	p.Update(0.8)
+	p.Update(0.1)
+	if p.RenderColor.R != 255 {
+		t.Errorf("Expected the overshoot clamped, got %v", p.RenderColor)
+	}
+}
+
+func Test_EasingZeroDuration(t *testing.T) {
+	easings := []engine.EasingFunction{
+		engine.LinearEasing,
+		engine.EaseInOutQuad, engine.EaseOutCubic, engine.EaseInQuart, engine.EaseOutQuint,
+		engine.EaseInSine, engine.EaseInOutExpo, engine.EaseOutCirc, engine.EaseInOutBack,
+		engine.EaseInElastic, engine.EaseOutElastic, engine.EaseInOutElastic,
+		engine.EaseInBounce, engine.EaseInOutBounce,
+		engine.NewCubicBezierEasing(0.25, 0.1, 0.25, 1.0),
+	}
+
+	for i, easing := range easings {
+		if v := easing(0.0, 10.0, 20.0, 0.0); v != 30.0 {
+			t.Errorf("%d: expected the end value, got %f", i, v)
+		}
+	}
+
+	// A zero duration particle shows its end color
+	p := engine.NewParticle()
+	p.IsAlive = true
+	p.Duration = 0.0
+	p.StartColor = color.RGBA{0, 0, 0, 255}
+	p.EndColor = color.RGBA{10, 20, 30, 255}
+	p.Easing = engine.EaseInOutSine
+	p.Update(0.1)
+	if p.RenderColor != p.EndColor {
+		t.Errorf("Expected the end color, got %v", p.RenderColor)
+	}
+}
@@ -0,0 +1,75 @@
+package tests
+
+import (
+	"math"
+	"testing"
+
+	"github.com/wdevore/GameEngine/engine"
+)
+
+func Test_FramePeriodModes(t *testing.T) {
+	e, platform := newHeadlessEngine()
+	defer e.Close()
+
+	if e.FramePeriod() != 1000.0/engine.DefaultFPS {
+		t.Errorf("Expected default period %f, got %f", 1000.0/engine.DefaultFPS, e.FramePeriod())
+	}
+
+	if err := e.SetTargetFPS(30.0); err != nil {
+		t.Fatal(err)
+	}
+	if e.FramePeriod() != 1000.0/30.0 {
+		t.Errorf("Expected period %f, got %f", 1000.0/30.0, e.FramePeriod())
+	}
+
+	if err := e.SetFrameMode(engine.FrameModeUncapped); err != nil {
+		t.Fatal(err)
+	}
+	if e.FramePeriod() != 0.0 {
+		t.Errorf("Expected uncapped period 0, got %f", e.FramePeriod())
+	}
+
+	// Losing focus throttles to the idle rate even when uncapped.
+	e.SetIdleFPS(5.0)
+	platform.SetFocus(false)
+	e.RunFrames(1)
+
+	if e.IsFocused() {
+		t.Error("Expected engine to be unfocused")
+	}
+	if e.FramePeriod() != 200.0 {
+		t.Errorf("Expected idle period 200, got %f", e.FramePeriod())
+	}
+
+	platform.SetFocus(true)
+	e.RunFrames(1)
+	if e.FramePeriod() != 0.0 {
+		t.Errorf("Expected uncapped period 0 after refocus, got %f", e.FramePeriod())
+	}
+}
+
+func Test_TargetFPSRejectsNonPositive(t *testing.T) {
+	e, _ := newHeadlessEngine()
+	defer e.Close()
+
+	for _, fps := range []float64{0.0, -30.0, math.NaN(), math.Inf(1)} {
+		if err := e.SetTargetFPS(fps); err != engine.ErrInvalidFPS {
+			t.Errorf("Expected %f to be rejected, got %v", fps, err)
+		}
+	}
+
+	if e.TargetFPS() != engine.DefaultFPS {
+		t.Errorf("Expected the default rate kept, got %f", e.TargetFPS())
+	}
+	if e.FramePeriod() != 1000.0/engine.DefaultFPS {
+		t.Errorf("Expected the default period, got %f", e.FramePeriod())
+	}
+
+	// Each frame still feeds one finite step into the timestep
+	steps := 0
+	e.GetRoot().Schedule(func(dt float64) { steps++ })
+	e.RunFrames(3)
+	if steps != 3 {
+		t.Errorf("Expected 3 steps, got %d", steps)
+	}
+}
@@ -0,0 +1,119 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/wdevore/GameEngine/engine"
+)
+
+func Test_ParentLinkage(t *testing.T) {
+	a := engine.NewGroupNode(nil, false)
+	b := engine.NewGroupNode(nil, false)
+	child := engine.NewRectangleNode(nil, false, false)
+
+	if child.Parent() != nil {
+		t.Error("Expected no parent before being added")
+	}
+
+	a.Add(child)
+	if child.Parent() != a {
+		t.Error("Expected parent to be set by Add")
+	}
+
+	// Adding elsewhere moves the node
+	b.Add(child)
+	if child.Parent() != b {
+		t.Error("Expected parent to follow the node")
+	}
+	if i, _ := a.Find(child); i != -1 {
+		t.Errorf("Expected node to be removed from old parent, found at %d", i)
+	}
+
+	b.Remove(child)
+	if child.Parent() != nil || len(b.Children()) != 0 {
+		t.Error("Expected Remove to clear the parent")
+	}
+}
+
+func Test_InsertAt(t *testing.T) {
+	g := engine.NewGroupNode(nil, false)
+	first := engine.NewRectangleNode(g, false, true)
+	last := engine.NewRectangleNode(g, false, true)
+
+	middle := engine.NewRectangleNode(nil, false, false)
+	g.InsertAt(1, middle)
+
+	front := engine.NewRectangleNode(nil, false, false)
+	g.InsertAt(-5, front)
+
+	children := g.Children()
+	if len(children) != 4 || children[0] != front || children[1] != first || children[2] != middle || children[3] != last {
+		t.Errorf("Unexpected order: %v", children)
+	}
+}
+
+func Test_RejectCycles(t *testing.T) {
+	root := engine.NewGroupNode(nil, false)
+	group := engine.NewGroupNode(root, true)
+
+	if err := engine.Reparent(root, group, false); err != engine.ErrSceneCycle {
+		t.Errorf("Expected ErrSceneCycle, got %v", err)
+	}
+
+	if err := engine.Reparent(group, group, false); err != engine.ErrSceneCycle {
+		t.Errorf("Expected ErrSceneCycle adding a node to itself, got %v", err)
+	}
+
+	defer func() {
+		if recover() != engine.ErrSceneCycle {
+			t.Error("Expected Add to panic with ErrSceneCycle")
+		}
+	}()
+	group.Add(root)
+}
+
+func Test_ReparentKeepsWorldTransform(t *testing.T) {
+	root := engine.NewGroupNode(nil, false)
+
+	from := engine.NewGroupNode(root, true)
+	from.SetPositionBy2Comp(100, 50)
+	from.SetRotationByDegree(30)
+
+	to := engine.NewGroupNode(root, true)
+	to.SetPositionBy2Comp(-20, 10)
+	to.SetRotationByDegree(-45)
+	to.SetScaleUniform(2)
+
+	child := engine.NewRectangleNode(from, true, true)
+	child.SetPositionBy2Comp(10, 5)
+	child.SetScaleUniform(3)
+
+	corner := engine.NewVector3With2Components(0.5, 0.5)
+	before := engine.NewVector3()
+	child.NodeToWorld(corner, before)
+
+	err := engine.Reparent(child, to, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if child.Parent() != to {
+		t.Error("Expected child to be moved")
+	}
+
+	after := engine.NewVector3()
+	child.NodeToWorld(corner, after)
+	if !nearVector(after, before.X, before.Y) {
+		t.Errorf("Expected world point %v to be kept, got %v", before, after)
+	}
+
+	// Without keepWorld the local transform is kept instead
+	err = engine.Reparent(child, from, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	child.NodeToWorld(corner, after)
+	if nearVector(after, before.X, before.Y) {
+		t.Error("Expected world point to move with the local transform kept")
+	}
+}
@@ -0,0 +1,89 @@
+package tests
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+	"github.com/wdevore/GameEngine/engine"
+)
+
+type recordingGame struct {
+	updates int
+	tDown   int
+	dt      float64
+}
+
+func (g *recordingGame) Update(dt float64, input *engine.Input) {
+	g.updates++
+	g.dt = dt
+	if input.IsKeyDown(sdl.SCANCODE_T) {
+		g.tDown++
+	}
+}
+
+func (g *recordingGame) Render(pixels *image.RGBA, alpha float64) {
+}
+
+func newHeadlessEngine() (*engine.Engine, *engine.HeadlessPlatform) {
+	platform := engine.NewHeadlessPlatform()
+	e := engine.NewEngineWithPlatform(64, 64, platform)
+	e.Initialize("Headless")
+	return e, platform
+}
+
+func Test_HeadlessRender(t *testing.T) {
+	e, platform := newHeadlessEngine()
+	defer e.Close()
+
+	orange := color.RGBA{255, 127, 0, 255}
+	rect := engine.NewRectangleNode(e.GetRoot(), true, true)
+	rect.SetPositionBy2Comp(32, 32)
+	rect.SetScaleUniform(10)
+	rect.SetColor(orange)
+
+	e.RunFrames(3)
+
+	if platform.Frames() != 3 {
+		t.Errorf("Expected 3 frames, got %d", platform.Frames())
+	}
+
+	if c := e.Pixels().RGBAAt(32, 32); c != orange {
+		t.Errorf("Expected center pixel %v, got %v", orange, c)
+	}
+
+	if c := e.Pixels().RGBAAt(2, 2); c != e.ClearColor {
+		t.Errorf("Expected corner pixel %v, got %v", e.ClearColor, c)
+	}
+}
+
+func Test_HeadlessInput(t *testing.T) {
+	e, platform := newHeadlessEngine()
+	defer e.Close()
+
+	game := new(recordingGame)
+	e.SetGame(game)
+
+	platform.PressKey(sdl.SCANCODE_T)
+	e.RunFrames(2)
+	platform.ReleaseKey(sdl.SCANCODE_T)
+	e.RunFrames(2)
+
+	if game.updates != 4 {
+		t.Errorf("Expected 4 updates, got %d", game.updates)
+	}
+	if game.tDown != 2 {
+		t.Errorf("Expected T down for 2 updates, got %d", game.tDown)
+	}
+
+	platform.PressKey(sdl.SCANCODE_ESCAPE)
+	e.RunFrames(10)
+
+	if e.IsRunning() {
+		t.Error("Expected ESC to stop the engine")
+	}
+	if platform.Frames() != 5 {
+		t.Errorf("Expected 5 frames, got %d", platform.Frames())
+	}
+}
@@ -0,0 +1,129 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+	"github.com/wdevore/GameEngine/engine"
+)
+
+func newTestInputMap(input *engine.Input) *engine.InputMap {
+	m := engine.NewInputMap(input)
+
+	m.AddAction("fire", engine.ActionDigital)
+	m.Bind("fire", engine.NewKeyBinding(sdl.SCANCODE_SPACE, engine.ModNone))
+	m.Bind("fire", engine.NewMouseBinding(engine.MouseButtonLeft))
+
+	m.AddAction("save", engine.ActionDigital)
+	m.Bind("save", engine.NewKeyBinding(sdl.SCANCODE_S, engine.ModCtrl))
+
+	m.AddAction("move_x", engine.ActionAxis)
+	m.Bind("move_x", engine.NewAxisKeyBinding(sdl.SCANCODE_LEFT, -1.0))
+	m.Bind("move_x", engine.NewAxisKeyBinding(sdl.SCANCODE_RIGHT, 1.0))
+
+	return m
+}
+
+func press(input *engine.Input, scancode sdl.Scancode) {
+	input.Dispatch(engine.NewKeyEvent(engine.EventKeyDown, scancode, engine.ModNone))
+}
+
+func release(input *engine.Input, scancode sdl.Scancode) {
+	input.Dispatch(engine.NewKeyEvent(engine.EventKeyUp, scancode, engine.ModNone))
+}
+
+func Test_InputMapDigitalAndAxis(t *testing.T) {
+	input := engine.NewInput()
+	m := newTestInputMap(input)
+
+	if err := m.Bind("jump", engine.NewKeyBinding(sdl.SCANCODE_W, engine.ModNone)); err == nil {
+		t.Error("Expected binding an undefined action to fail")
+	}
+
+	input.Dispatch(engine.NewMouseButtonEvent(engine.EventMouseButtonDown, engine.MouseButtonLeft, 0, 0))
+	if !m.IsActive("fire") || !m.IsPressed("fire") {
+		t.Error("Expected mouse to fire")
+	}
+
+	press(input, sdl.SCANCODE_LEFT)
+	press(input, sdl.SCANCODE_RIGHT)
+	if m.Axis("move_x") != 0.0 {
+		t.Errorf("Expected opposing keys to cancel, got %f", m.Axis("move_x"))
+	}
+	release(input, sdl.SCANCODE_RIGHT)
+	if m.Axis("move_x") != -1.0 {
+		t.Errorf("Expected -1, got %f", m.Axis("move_x"))
+	}
+}
+
+func Test_InputMapModifiers(t *testing.T) {
+	input := engine.NewInput()
+	m := newTestInputMap(input)
+
+	press(input, sdl.SCANCODE_S)
+	if m.IsActive("save") {
+		t.Error("Expected save to require ctrl")
+	}
+	release(input, sdl.SCANCODE_S)
+
+	press(input, sdl.SCANCODE_LCTRL)
+	press(input, sdl.SCANCODE_S)
+	if !m.IsActive("save") || !m.IsPressed("save") {
+		t.Error("Expected ctrl+S to save")
+	}
+}
+
+func Test_InputMapSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bindings.json")
+
+	if err := newTestInputMap(engine.NewInput()).Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	input := engine.NewInput()
+	m := engine.NewInputMap(input)
+	if err := m.Load(path); err != nil {
+		t.Fatal(err)
+	}
+
+	save := m.Action("save")
+	if save == nil || len(save.Bindings) != 1 || save.Bindings[0].Modifiers != engine.ModCtrl {
+		t.Fatalf("Expected save bound to ctrl+S, got %v", save)
+	}
+	if m.Action("move_x").Kind != engine.ActionAxis {
+		t.Error("Expected move_x to be an axis")
+	}
+
+	press(input, sdl.SCANCODE_RIGHT)
+	if m.Axis("move_x") != 1.0 {
+		t.Errorf("Expected 1, got %f", m.Axis("move_x"))
+	}
+}
+
+func Test_InputMapLoadRejectsBadFiles(t *testing.T) {
+	bad := map[string]string{
+		"null action":    `{"actions": [null]}`,
+		"unknown device": `{"actions": [{"name": "fire", "kind": "digital", "bindings": [{"device": "pad", "code": 1}]}]}`,
+		"bad scancode":   `{"actions": [{"name": "fire", "kind": "digital", "bindings": [{"device": "key", "code": -1}]}]}`,
+		"bad button":     `{"actions": [{"name": "fire", "kind": "digital", "bindings": [{"device": "mouse", "code": 9}]}]}`,
+		"no name":        `{"actions": [{"kind": "axis"}]}`,
+		"numeric kind":   `{"actions": [{"name": "fire", "kind": 7}]}`,
+	}
+
+	for name, data := range bad {
+		path := filepath.Join(t.TempDir(), "bindings.json")
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		m := newTestInputMap(engine.NewInput())
+		if err := m.Load(path); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+		if len(m.Action("fire").Bindings) != 2 {
+			t.Errorf("%s: expected the map unchanged", name)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package tests
+
+import (
+	"image"
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+	"github.com/wdevore/GameEngine/engine"
+)
+
+// edgeGame records the key edges seen by each update.
+type edgeGame struct {
+	pressed  []bool
+	released []bool
+	text     string
+}
+
+func (g *edgeGame) Update(dt float64, input *engine.Input) {
+	g.pressed = append(g.pressed, input.IsKeyPressed(sdl.SCANCODE_SPACE))
+	g.released = append(g.released, input.IsKeyReleased(sdl.SCANCODE_SPACE))
+	g.text += input.Text()
+}
+
+func (g *edgeGame) Render(pixels *image.RGBA, alpha float64) {
+}
+
+func Test_InputEdgesLastOneStep(t *testing.T) {
+	e, platform := newHeadlessEngine()
+	defer e.Close()
+
+	game := new(edgeGame)
+	e.SetGame(game)
+
+	platform.PressKey(sdl.SCANCODE_SPACE)
+	platform.TypeText("hi")
+	e.RunFrames(2)
+	platform.ReleaseKey(sdl.SCANCODE_SPACE)
+	e.RunFrames(2)
+
+	expectPressed := []bool{true, false, false, false}
+	expectReleased := []bool{false, false, true, false}
+	for i := range expectPressed {
+		if game.pressed[i] != expectPressed[i] || game.released[i] != expectReleased[i] {
+			t.Errorf("Step %d: expected pressed=%v released=%v, got %v %v",
+				i, expectPressed[i], expectReleased[i], game.pressed[i], game.released[i])
+		}
+	}
+
+	if game.text != "hi" {
+		t.Errorf("Expected text 'hi', got '%s'", game.text)
+	}
+	if e.Input().IsKeyDown(sdl.SCANCODE_SPACE) {
+		t.Error("Expected space to be up")
+	}
+}
+
+func Test_InputListenerPriorityAndConsume(t *testing.T) {
+	e, platform := newHeadlessEngine()
+	defer e.Close()
+
+	input := e.Input()
+	order := []string{}
+
+	input.AddListener(engine.PriorityDefault, func(event engine.IEvent) {
+		order = append(order, "low")
+	})
+	input.AddListener(10, func(event engine.IEvent) {
+		order = append(order, "high")
+		if event.Type() == engine.EventKeyDown {
+			// Prevents both the low listener and the engine's ESC handling
+			event.Consume()
+		}
+	})
+
+	platform.PressKey(sdl.SCANCODE_ESCAPE)
+	platform.MoveMouse(5, 7)
+	e.RunFrames(1)
+
+	expected := []string{"high", "high", "low"}
+	if len(order) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, order)
+	}
+	for i := range expected {
+		if order[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected, order)
+		}
+	}
+
+	if !e.IsRunning() {
+		t.Error("Expected consumed ESC not to stop the engine")
+	}
+	if x, y := input.MousePosition(); x != 5 || y != 7 {
+		t.Errorf("Expected mouse <5, 7>, got <%d, %d>", x, y)
+	}
+	// State is tracked even though the key event was consumed.
+	if !input.IsKeyDown(sdl.SCANCODE_ESCAPE) {
+		t.Error("Expected ESC to be down")
+	}
+}
+
+func Test_InputRemoveListener(t *testing.T) {
+	input := engine.NewInput()
+
+	calls := 0
+	id := input.AddListener(engine.PriorityDefault, func(event engine.IEvent) {
+		calls++
+	})
+
+	input.Dispatch(engine.NewQuitEvent())
+	input.RemoveListener(id)
+	input.Dispatch(engine.NewQuitEvent())
+
+	if calls != 1 {
+		t.Errorf("Expected 1 call, got %d", calls)
+	}
+}
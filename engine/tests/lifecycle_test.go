@@ -0,0 +1,151 @@
+package tests
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/wdevore/GameEngine/engine"
+)
+
+// lifecycleNode records its lifecycle calls.
+type lifecycleNode struct {
+	engine.INode
+	log *[]string
+}
+
+func newLifecycleNode(name string, log *[]string) *lifecycleNode {
+	n := engine.NewRectangleNode(nil, false, false)
+	n.SetName(name)
+	return &lifecycleNode{INode: n, log: log}
+}
+
+func (n *lifecycleNode) OnEnter() {
+	*n.log = append(*n.log, n.Name()+".enter")
+}
+
+func (n *lifecycleNode) OnExit() {
+	*n.log = append(*n.log, n.Name()+".exit")
+}
+
+func (n *lifecycleNode) OnAdded(parent engine.IGroupNode) {
+	*n.log = append(*n.log, n.Name()+".added")
+}
+
+// lifecycleGroup is a group that records its lifecycle calls.
+type lifecycleGroup struct {
+	engine.IGroupNode
+	log *[]string
+}
+
+func newLifecycleGroup(name string, log *[]string) *lifecycleGroup {
+	g := engine.NewGroupNode(nil, false)
+	g.SetName(name)
+	return &lifecycleGroup{IGroupNode: g, log: log}
+}
+
+func (g *lifecycleGroup) OnEnter() {
+	*g.log = append(*g.log, g.Name()+".enter")
+}
+
+func (g *lifecycleGroup) OnExit() {
+	*g.log = append(*g.log, g.Name()+".exit")
+}
+
+func (g *lifecycleGroup) OnAdded(parent engine.IGroupNode) {
+	*g.log = append(*g.log, g.Name()+".added")
+}
+
+func Test_LifecycleOnRunningParent(t *testing.T) {
+	e, _ := newHeadlessEngine()
+	defer e.Close()
+
+	var log []string
+	leaf := newLifecycleNode("leaf", &log)
+
+	// Not running yet so only added
+	e.GetRoot().Add(leaf)
+	if leaf.IsRunning() {
+		t.Error("Expected leaf not to be running before the engine runs")
+	}
+
+	e.RunFrames(1)
+	if !leaf.IsRunning() {
+		t.Error("Expected leaf to be running")
+	}
+
+	e.GetRoot().Remove(leaf)
+	if leaf.IsRunning() {
+		t.Error("Expected leaf to stop running once removed")
+	}
+
+	expected := []string{"leaf.added", "leaf.enter", "leaf.exit"}
+	if !reflect.DeepEqual(log, expected) {
+		t.Errorf("Expected %v, got %v", expected, log)
+	}
+}
+
+func Test_LifecycleSubtreeOrder(t *testing.T) {
+	var log []string
+	s := engine.NewBaseScene("Level")
+	m := engine.NewSceneManager()
+	m.Push(s, nil)
+
+	// A subtree built while detached enters in one go, parents first
+	group := newLifecycleGroup("group", &log)
+	leaf := newLifecycleNode("leaf", &log)
+	group.Add(leaf)
+
+	s.Root().Add(group)
+
+	expected := []string{"leaf.added", "group.added", "group.enter", "leaf.enter"}
+	if !reflect.DeepEqual(log, expected) {
+		t.Errorf("Expected %v, got %v", expected, log)
+	}
+
+	// Leaving the scene exits children first
+	log = nil
+	m.Replace(engine.NewBaseScene("Menu"), nil)
+
+	expected = []string{"leaf.exit", "group.exit"}
+	if !reflect.DeepEqual(log, expected) {
+		t.Errorf("Expected %v, got %v", expected, log)
+	}
+	if s.Root().IsRunning() {
+		t.Error("Expected the replaced scene's root to stop running")
+	}
+}
+
+func Test_LifecycleEngineRootExitsForScene(t *testing.T) {
+	e, _ := newHeadlessEngine()
+	defer e.Close()
+
+	var log []string
+	leaf := newLifecycleNode("leaf", &log)
+	root := e.GetRoot()
+	root.Add(leaf)
+
+	timer := leaf.Schedule(func(dt float64) {})
+
+	e.RunFrames(1)
+	if !leaf.IsRunning() {
+		t.Fatal("Expected the engine's root to run without a scene")
+	}
+
+	e.Scenes().Push(engine.NewBaseScene("Game"), nil)
+
+	expected := []string{"leaf.added", "leaf.enter", "leaf.exit"}
+	if !reflect.DeepEqual(log, expected) {
+		t.Errorf("Expected %v, got %v", expected, log)
+	}
+	if leaf.IsRunning() || root.IsRunning() {
+		t.Error("Expected the engine's root to stop running")
+	}
+	if timer.IsActive() {
+		t.Error("Expected the root's timers to be cancelled")
+	}
+
+	e.RunFrames(1)
+	if leaf.IsRunning() {
+		t.Error("Expected the engine's root to stay exited while a scene runs")
+	}
+}
@@ -0,0 +1,131 @@
+package tests
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/wdevore/GameEngine/engine"
+)
+
+func Test_RegisteredNodeTypes(t *testing.T) {
+	names := engine.RegisteredNodeTypes()
+
+	found := map[string]bool{}
+	for _, name := range names {
+		found[name] = true
+	}
+
+	for _, name := range []string{"Group", "Rectangle", "Star"} {
+		if !found[name] {
+			t.Errorf("Expected '%s' to be registered: %v", name, names)
+		}
+	}
+}
+
+func Test_CreateNodeFromProperties(t *testing.T) {
+	root := engine.NewGroupNode(nil, false)
+
+	// Console style string values convert like native ones.
+	n, err := engine.CreateNode("Rectangle", root, engine.Properties{
+		"name":     "Box",
+		"x":        "10",
+		"y":        20,
+		"scale":    25.0,
+		"rotation": 90.0,
+		"visible":  "false",
+		"color":    "#ff7f00c8",
+		"centered": true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, fno := root.Find(n); fno == nil {
+		t.Error("Expected node to be added to parent")
+	}
+
+	r := n.(*engine.RectangleNode)
+	if r.Name() != "Box" || !r.IsCentered() || r.IsVisible() {
+		t.Errorf("Unexpected node: %s centered %v visible %v", r.Name(), r.IsCentered(), r.IsVisible())
+	}
+	if r.Position().X != 10 || r.Position().Y != 20 {
+		t.Errorf("Unexpected position: %v", r.Position())
+	}
+	if r.Scale().X != 25 || r.Scale().Y != 25 {
+		t.Errorf("Unexpected scale: %v", r.Scale())
+	}
+	if !near(r.Rotation(), 90*engine.DegreeToRadians) {
+		t.Errorf("Unexpected rotation: %f", r.Rotation())
+	}
+	if r.Color() != (color.RGBA{255, 127, 0, 200}) {
+		t.Errorf("Unexpected color: %v", r.Color())
+	}
+}
+
+func Test_CreateNodeUserType(t *testing.T) {
+	n, err := engine.CreateNode("Star", nil, engine.Properties{"points": "7"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n.(*starNode).points != 7 {
+		t.Errorf("Expected 7 points, got %d", n.(*starNode).points)
+	}
+}
+
+func Test_CreateNodeErrors(t *testing.T) {
+	if _, err := engine.CreateNode("Hexagon", nil, nil); err == nil {
+		t.Error("Expected unknown type error")
+	}
+
+	if _, err := engine.CreateNode("Rectangle", nil, engine.Properties{"x": "left"}); err == nil {
+		t.Error("Expected bad number error")
+	}
+
+	if _, err := engine.CreateNode("Rectangle", nil, engine.Properties{"centered": 3}); err == nil {
+		t.Error("Expected bad bool error")
+	}
+}
+
+// setterCounter counts calls to the transform and size setters.
+type setterCounter struct {
+	engine.INode
+	calls int
+}
+
+func (n *setterCounter) SetPositionBy2Comp(x, y float64) {
+	n.calls++
+	n.INode.SetPositionBy2Comp(x, y)
+}
+
+func (n *setterCounter) SetScale(scale *engine.Vector3) {
+	n.calls++
+	n.INode.SetScale(scale)
+}
+
+func (n *setterCounter) SetContentSize(w, h float64) {
+	n.calls++
+	n.INode.SetContentSize(w, h)
+}
+
+func Test_ApplyPropertiesOnlySetsPresentKeys(t *testing.T) {
+	n := &setterCounter{INode: engine.NewRectangleNode(nil, false, false)}
+	n.INode.SetContentSize(3, 4)
+
+	if err := engine.ApplyProperties(n, engine.Properties{"name": "n", "zIndex": 2}); err != nil {
+		t.Fatal(err)
+	}
+	if n.calls != 0 {
+		t.Errorf("Expected no transform or size setters called, got %d", n.calls)
+	}
+	if n.Name() != "n" || n.ZIndex() != 2 {
+		t.Errorf("Unexpected name %s or z-index %d", n.Name(), n.ZIndex())
+	}
+
+	if err := engine.ApplyProperties(n, engine.Properties{"width": 5}); err != nil {
+		t.Fatal(err)
+	}
+	if n.calls != 1 || n.ContentSize().X != 5 || n.ContentSize().Y != 4 {
+		t.Errorf("Expected only the width changed, got %v after %d calls", n.ContentSize(), n.calls)
+	}
+}
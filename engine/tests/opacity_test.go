@@ -0,0 +1,75 @@
+package tests
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/wdevore/GameEngine/engine"
+)
+
+func Test_OpacityCascades(t *testing.T) {
+	e, _ := newHeadlessEngine()
+	defer e.Close()
+	e.ClearColor = color.RGBA{0, 0, 0, 255}
+
+	group := engine.NewGroupNode(e.GetRoot(), true)
+	outer := engine.NewGroupNode(group, true)
+	rect := engine.NewRectangleNode(outer, false, true)
+	rect.SetScaleUniform(64)
+	rect.SetColor(color.RGBA{200, 200, 200, 255})
+
+	// A single change fades the whole subtree, combining with descendants
+	group.SetOpacity(0.5)
+	outer.SetOpacity(0.5)
+	e.RunFrames(1)
+
+	c := e.Pixels().RGBAAt(32, 32)
+	if c.R < 48 || c.R > 52 {
+		t.Errorf("Expected a quarter of 200 over black, got %v", c)
+	}
+
+	group.SetOpacity(0)
+	e.RunFrames(1)
+	if c = e.Pixels().RGBAAt(32, 32); c != e.ClearColor {
+		t.Errorf("Expected nothing drawn when transparent, got %v", c)
+	}
+
+	group.SetOpacity(2)
+	if group.Opacity() != 1 {
+		t.Errorf("Expected opacity clamped to 1, got %f", group.Opacity())
+	}
+}
+
+func Test_TintCascades(t *testing.T) {
+	e, _ := newHeadlessEngine()
+	defer e.Close()
+
+	group := engine.NewGroupNode(e.GetRoot(), true)
+	left := engine.NewRectangleNode(group, false, true)
+	left.SetScaleUniform(32)
+	left.SetColor(color.RGBA{255, 255, 255, 255})
+
+	right := engine.NewRectangleNode(group, false, true)
+	right.SetPositionBy2Comp(32, 0)
+	right.SetScaleUniform(32)
+	right.SetColor(color.RGBA{0, 200, 255, 255})
+	right.SetTint(color.RGBA{255, 255, 0, 255})
+
+	// Flash the group red
+	group.SetTint(color.RGBA{255, 0, 0, 255})
+	e.RunFrames(1)
+
+	if c := e.Pixels().RGBAAt(10, 10); c != (color.RGBA{255, 0, 0, 255}) {
+		t.Errorf("Expected white tinted red, got %v", c)
+	}
+	// Tints multiply: red * yellow leaves nothing of cyan-ish blue
+	if c := e.Pixels().RGBAAt(42, 10); c != (color.RGBA{0, 0, 0, 255}) {
+		t.Errorf("Expected combined tint to remove green and blue, got %v", c)
+	}
+
+	group.SetTint(color.RGBA{255, 255, 255, 255})
+	e.RunFrames(1)
+	if c := e.Pixels().RGBAAt(10, 10); c != left.Color() {
+		t.Errorf("Expected tint removed, got %v", c)
+	}
+}
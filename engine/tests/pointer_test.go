@@ -0,0 +1,149 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/wdevore/GameEngine/engine"
+)
+
+// pointerLog records the pointer events a node receives.
+type pointerLog struct {
+	events []engine.PointerEventType
+	dx     float64
+}
+
+func (l *pointerLog) handle(event *engine.PointerEvent) {
+	l.events = append(l.events, event.Type)
+	if event.Type == engine.PointerDrag {
+		l.dx += event.DX
+	}
+}
+
+func (l *pointerLog) has(eventType engine.PointerEventType) bool {
+	for _, e := range l.events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func Test_HitTestTransformedHierarchy(t *testing.T) {
+	root := engine.NewGroupNode(nil, false)
+
+	group := engine.NewGroupNode(root, true)
+	group.SetPositionBy2Comp(100, 100)
+	group.SetRotationByDegree(90)
+
+	rect := engine.NewRectangleNode(group, true, true)
+	rect.SetPositionBy2Comp(20, 0)
+	rect.SetScale(engine.NewVector3With2Components(10, 4))
+
+	// Rotating the group by +90 (CW with Y down) moves the rect to 100,120
+	// and turns it so it is 4 wide and 10 tall.
+	if hit := engine.HitTest(root, 100, 120); hit != rect {
+		t.Errorf("Expected rect at <100, 120>, got %v", hit)
+	}
+	if hit := engine.HitTest(root, 101.5, 124); hit != rect {
+		t.Errorf("Expected rect at <101.5, 124>, got %v", hit)
+	}
+	if hit := engine.HitTest(root, 104, 121); hit != nil {
+		t.Errorf("Expected no hit at <104, 121>, got %v", hit)
+	}
+	if hit := engine.HitTest(root, 120, 100); hit != nil {
+		t.Errorf("Expected no hit at the unrotated position, got %v", hit)
+	}
+}
+
+func Test_HitTestTopMost(t *testing.T) {
+	root := engine.NewGroupNode(nil, false)
+
+	bottom := engine.NewRectangleNode(root, true, true)
+	bottom.SetPositionBy2Comp(10, 10)
+	bottom.SetScaleUniform(10)
+
+	top := engine.NewRectangleNode(root, true, true)
+	top.SetPositionBy2Comp(12, 12)
+	top.SetScaleUniform(10)
+
+	if hit := engine.HitTest(root, 11, 11); hit != top {
+		t.Errorf("Expected top, got %v", hit)
+	}
+
+	top.SetInvisible()
+	if hit := engine.HitTest(root, 11, 11); hit != bottom {
+		t.Errorf("Expected bottom when top is invisible, got %v", hit)
+	}
+}
+
+func Test_HitTestDeepTree(t *testing.T) {
+	root := engine.NewGroupNode(nil, false)
+
+	// Deeper than AffinePool holds transforms
+	parent := root
+	for i := 0; i < 200; i++ {
+		g := engine.NewGroupNode(parent, true)
+		g.SetPositionBy2Comp(1, 0)
+		parent = g
+	}
+	rect := engine.NewRectangleNode(parent, true, true)
+	rect.SetScaleUniform(10)
+
+	if hit := engine.HitTest(root, 200, 0); hit != rect {
+		t.Errorf("Expected rect, got %v", hit)
+	}
+	if hit := engine.HitTest(root, 0, 0); hit != nil {
+		t.Errorf("Expected nothing, got %v", hit)
+	}
+}
+
+func Test_PointerEvents(t *testing.T) {
+	e, platform := newHeadlessEngine()
+	defer e.Close()
+
+	rect := engine.NewRectangleNode(e.GetRoot(), true, true)
+	rect.SetPositionBy2Comp(20, 20)
+	rect.SetScaleUniform(10)
+
+	log := new(pointerLog)
+	rect.SetPointerHandler(log.handle)
+
+	platform.MoveMouse(20, 20)
+	platform.PressButton(engine.MouseButtonLeft)
+	e.RunFrames(1)
+
+	if e.Pointer().Hover() != rect {
+		t.Errorf("Expected rect under the pointer")
+	}
+
+	// Drag off the node, the drag continues but there is no click.
+	platform.MoveMouse(40, 20)
+	platform.ReleaseButton(engine.MouseButtonLeft)
+	e.RunFrames(1)
+
+	expected := []engine.PointerEventType{
+		engine.PointerEnter, engine.PointerDown, engine.PointerLeave, engine.PointerDrag, engine.PointerUp,
+	}
+	if len(log.events) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, log.events)
+	}
+	for i := range expected {
+		if log.events[i] != expected[i] {
+			t.Fatalf("Expected %v, got %v", expected, log.events)
+		}
+	}
+	if log.dx != 20 {
+		t.Errorf("Expected drag of 20, got %f", log.dx)
+	}
+
+	// A press and release over the node clicks.
+	log.events = nil
+	platform.MoveMouse(22, 22)
+	platform.PressButton(engine.MouseButtonLeft)
+	platform.ReleaseButton(engine.MouseButtonLeft)
+	e.RunFrames(1)
+
+	if !log.has(engine.PointerClick) {
+		t.Errorf("Expected a click, got %v", log.events)
+	}
+}
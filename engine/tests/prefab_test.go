@@ -0,0 +1,119 @@
+package tests
+
+import (
+	"image/color"
+	"reflect"
+	"testing"
+
+	"github.com/wdevore/GameEngine/engine"
+)
+
+// buildEnemy is a composite node: a body with a turret holding a barrel.
+func buildEnemy() engine.IGroupNode {
+	enemy := engine.NewGroupNode(nil, false)
+	enemy.SetName("enemy")
+	enemy.AddTag("enemy")
+
+	body := engine.NewRectangleNode(enemy, true, true)
+	body.SetName("body")
+	body.SetScaleUniform(20)
+
+	turret := engine.NewGroupNode(enemy, true)
+	turret.SetName("turret")
+	turret.SetRotationByDegree(45)
+
+	barrel := &starNode{INode: engine.NewRectangleNode(nil, false, false), points: 5}
+	barrel.SetName("barrel")
+	barrel.SetColor(color.RGBA{255, 0, 0, 255})
+	turret.Add(barrel)
+
+	return enemy
+}
+
+func Test_CloneIsDeepAndDetached(t *testing.T) {
+	root := engine.NewGroupNode(nil, false)
+	enemy := buildEnemy()
+	root.Add(enemy)
+
+	clone, err := engine.Clone(enemy)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if clone.Parent() != nil {
+		t.Error("Expected the clone to be detached")
+	}
+
+	original, _ := engine.DescribeNode(enemy)
+	copied, _ := engine.DescribeNode(clone)
+	if !reflect.DeepEqual(original, copied) {
+		t.Errorf("Expected an identical subtree:\n%+v\n%+v", original, copied)
+	}
+
+	// Changing the clone leaves the original alone
+	barrel, _ := engine.QueryFirst(clone, "turret/barrel")
+	barrel.SetPositionBy2Comp(5, 5)
+	if p := engine.FindByName(enemy, "barrel").Position(); p.X != 0 {
+		t.Errorf("Expected the original barrel unchanged, got %v", p)
+	}
+}
+
+func Test_PrefabInstances(t *testing.T) {
+	prefab, err := engine.NewPrefab("enemy", buildEnemy())
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine.RegisterPrefab(prefab)
+
+	root := engine.NewGroupNode(nil, false)
+
+	for i := 0; i < 10; i++ {
+		_, err = engine.InstantiatePrefab("enemy", root, map[string]engine.Properties{
+			"":              {"x": float64(i * 10)},
+			"turret/barrel": {"color": "#00ff00ff", "points": i},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(root.Children()) != 10 {
+		t.Fatalf("Expected 10 instances, got %d", len(root.Children()))
+	}
+
+	third := root.Children()[3]
+	if third.Position().X != 30 || !third.HasTag("enemy") {
+		t.Errorf("Expected root override, got %v", third)
+	}
+
+	barrel := engine.FindByName(third, "barrel").(*starNode)
+	if barrel.Color() != (color.RGBA{0, 255, 0, 255}) {
+		t.Errorf("Expected common override, got %v", barrel.Color())
+	}
+	if barrel.points != 3 {
+		t.Errorf("Expected type specific override, got %d points", barrel.points)
+	}
+
+	// Overrides don't leak into the template
+	plain, err := prefab.Instantiate(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	barrel = engine.FindByName(plain, "barrel").(*starNode)
+	if barrel.points != 5 || barrel.Color() != (color.RGBA{255, 0, 0, 255}) {
+		t.Errorf("Expected the template's barrel, got %d points %v", barrel.points, barrel.Color())
+	}
+}
+
+func Test_PrefabErrors(t *testing.T) {
+	prefab, _ := engine.NewPrefab("enemy", buildEnemy())
+
+	_, err := prefab.Instantiate(nil, map[string]engine.Properties{"turret/cannon": {"x": 1}})
+	if err == nil {
+		t.Error("Expected unknown path error")
+	}
+
+	if _, err = engine.InstantiatePrefab("dragon", nil, nil); err == nil {
+		t.Error("Expected unknown prefab error")
+	}
+}
@@ -0,0 +1,133 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/wdevore/GameEngine/engine"
+)
+
+func buildQueryScene() engine.IGroupNode {
+	root := engine.NewGroupNode(nil, false)
+	root.SetName("Root")
+
+	white := engine.NewGroupNode(root, true)
+	white.SetName("whiteGroup")
+
+	wrect := engine.NewRectangleNode(white, false, true)
+	wrect.SetName("WhiteRect")
+	wrect.AddTag("shape")
+
+	orange := engine.NewGroupNode(white, true)
+	orange.SetName("orangeGroup")
+
+	orect := engine.NewRectangleNode(orange, false, true)
+	orect.SetName("OrangeRect")
+	orect.AddTag("shape")
+	orect.AddTag("enemy")
+
+	oring := engine.NewRectangleNode(orange, false, true)
+	oring.SetName("OrangeRing")
+
+	boss := engine.NewRectangleNode(root, false, true)
+	boss.SetName("OrangeRect")
+	boss.AddTag("enemy")
+
+	return root
+}
+
+func names(nodes []engine.INode) []string {
+	var list []string
+	for _, n := range nodes {
+		list = append(list, n.Name())
+	}
+	return list
+}
+
+func Test_FindByName(t *testing.T) {
+	root := buildQueryScene()
+
+	n := engine.FindByName(root, "OrangeRect")
+	if n == nil || n.Parent().Name() != "orangeGroup" {
+		t.Errorf("Expected the first OrangeRect in tree order, got %v", n)
+	}
+
+	all := engine.FindAllByName(root, "OrangeRect").Collect()
+	if len(all) != 2 || all[1].Parent() != root {
+		t.Errorf("Expected both OrangeRects, got %v", all)
+	}
+
+	if engine.FindByName(root, "Orange*") != nil {
+		t.Error("Expected names not to be patterns")
+	}
+}
+
+func Test_FindByTag(t *testing.T) {
+	root := buildQueryScene()
+
+	it := engine.FindByTag(root, "enemy")
+	count := 0
+	for it.Next() {
+		if !it.Node().HasTag("enemy") {
+			t.Errorf("Unexpected node %v", it.Node())
+		}
+		count++
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 enemies, got %d", count)
+	}
+
+	leaf := engine.FindByName(root, "WhiteRect")
+	leaf.RemoveTag("shape")
+	if got := names(engine.FindByTag(root, "shape").Collect()); len(got) != 1 || got[0] != "OrangeRect" {
+		t.Errorf("Expected only OrangeRect tagged shape, got %v", got)
+	}
+}
+
+func Test_QueryPaths(t *testing.T) {
+	root := buildQueryScene()
+
+	cases := []struct {
+		query    string
+		expected []string
+	}{
+		{"whiteGroup/orangeGroup", []string{"orangeGroup"}},
+		{"whiteGroup/orangeGroup/*", []string{"OrangeRect", "OrangeRing"}},
+		{"/whiteGroup/*", []string{"WhiteRect", "orangeGroup"}},
+		{"whiteGroup/orangeGroup/Orange?ect", []string{"OrangeRect"}},
+		{"**/Orange*", []string{"OrangeRect", "OrangeRing", "OrangeRect"}},
+		{"whiteGroup/**", []string{"WhiteRect", "orangeGroup", "OrangeRect", "OrangeRing"}},
+		{"**/@enemy", []string{"OrangeRect", "OrangeRect"}},
+		{"whiteGroup/**/@shape", []string{"WhiteRect", "OrangeRect"}},
+		{"**/**/OrangeRing", []string{"OrangeRing"}},
+		{"**/whiteGroup/**/OrangeRing", []string{"OrangeRing"}},
+		{"blackGroup/*", nil},
+	}
+
+	for _, c := range cases {
+		it, err := engine.Query(root, c.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got := names(it.Collect())
+		if len(got) != len(c.expected) {
+			t.Errorf("%s: expected %v, got %v", c.query, c.expected, got)
+			continue
+		}
+		for i := range got {
+			if got[i] != c.expected[i] {
+				t.Errorf("%s: expected %v, got %v", c.query, c.expected, got)
+				break
+			}
+		}
+	}
+
+	if _, err := engine.Query(root, "whiteGroup/[a-"); err == nil {
+		t.Error("Expected a bad pattern error")
+	}
+
+	n, err := engine.QueryFirst(root, "")
+	if err != nil || n != root {
+		t.Errorf("Expected empty query to match root, got %v", n)
+	}
+}
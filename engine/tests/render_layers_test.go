@@ -0,0 +1,148 @@
+package tests
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/wdevore/GameEngine/engine"
+)
+
+func fullRect(parent engine.IGroupNode, name string, fill color.RGBA) engine.INode {
+	r := engine.NewRectangleNode(parent, false, true)
+	r.SetName(name)
+	r.SetScaleUniform(64)
+	r.SetColor(fill)
+	return r
+}
+
+func Test_ZIndexOrder(t *testing.T) {
+	e, _ := newHeadlessEngine()
+	defer e.Close()
+
+	root := e.GetRoot()
+	a := fullRect(root, "a", color.RGBA{255, 0, 0, 255})
+	b := fullRect(root, "b", color.RGBA{0, 0, 255, 255})
+	c := fullRect(root, "c", color.RGBA{0, 255, 0, 255})
+
+	// Insertion order puts c on top
+	e.RunFrames(1)
+	if p := e.Pixels().RGBAAt(32, 32); p != c.Color() {
+		t.Errorf("Expected last added on top, got %v", p)
+	}
+
+	a.SetZIndex(1)
+	e.RunFrames(1)
+	if p := e.Pixels().RGBAAt(32, 32); p != a.Color() {
+		t.Errorf("Expected highest z-index on top, got %v", p)
+	}
+
+	// Equal z-indices keep insertion order
+	children := root.Children()
+	if children[0] != b || children[1] != c || children[2] != a {
+		t.Errorf("Unexpected order: %v", children)
+	}
+
+	if hit := engine.HitTest(root, 32, 32); hit != a {
+		t.Errorf("Expected to hit a, got %v", hit)
+	}
+}
+
+func Test_BringToFrontSendToBack(t *testing.T) {
+	root := engine.NewGroupNode(nil, false)
+	a := engine.NewRectangleNode(root, false, true)
+	b := engine.NewRectangleNode(root, false, true)
+	c := engine.NewRectangleNode(root, false, true)
+	c.SetZIndex(3)
+
+	root.BringToFront(a)
+	children := root.Children()
+	if children[2] != a || a.ZIndex() != 3 {
+		t.Errorf("Expected a on top with z 3, got %v z %d", children, a.ZIndex())
+	}
+
+	root.SendToBack(c)
+	children = root.Children()
+	if children[0] != c || c.ZIndex() != 0 {
+		t.Errorf("Expected c at the back with z 0, got %v z %d", children, c.ZIndex())
+	}
+	if children[1] != b || children[2] != a {
+		t.Errorf("Unexpected order: %v", children)
+	}
+}
+
+func Test_RenderLayers(t *testing.T) {
+	e, _ := newHeadlessEngine()
+	defer e.Close()
+
+	root := e.GetRoot()
+
+	// The HUD is first in the tree and nested but draws over the world
+	hudGroup := engine.NewGroupNode(root, true)
+	hudGroup.SetLayer(engine.LayerHUD)
+	hud := engine.NewRectangleNode(hudGroup, false, true)
+	hud.SetScaleUniform(32)
+	hud.SetColor(color.RGBA{0, 255, 0, 255})
+
+	world := fullRect(root, "world", color.RGBA{255, 0, 0, 255})
+
+	// The background is last in the tree but draws under the world
+	background := fullRect(root, "background", color.RGBA{0, 0, 255, 255})
+	background.SetLayer(engine.LayerBackground)
+
+	e.RunFrames(1)
+
+	if p := e.Pixels().RGBAAt(10, 10); p != hud.Color() {
+		t.Errorf("Expected HUD on top, got %v", p)
+	}
+	if p := e.Pixels().RGBAAt(50, 50); p != world.Color() {
+		t.Errorf("Expected world over background, got %v", p)
+	}
+
+	if hit := engine.HitTest(root, 10, 10); hit != hud {
+		t.Errorf("Expected to hit the HUD, got %v", hit)
+	}
+	if hit := engine.HitTest(root, 50, 50); hit != world {
+		t.Errorf("Expected to hit the world, got %v", hit)
+	}
+}
+
+func Test_RenderLayersFollowChanges(t *testing.T) {
+	e, _ := newHeadlessEngine()
+	defer e.Close()
+
+	root := e.GetRoot()
+	world := fullRect(root, "world", color.RGBA{255, 0, 0, 255})
+	top := fullRect(root, "top", color.RGBA{0, 0, 255, 255})
+	top.SetLayer(engine.LayerBackground)
+
+	expect := func(step string, node engine.INode) {
+		t.Helper()
+		e.RunFrames(1)
+		if p := e.Pixels().RGBAAt(32, 32); p != node.Color() {
+			t.Errorf("%s: expected %s drawn on top, got %v", step, node.Name(), p)
+		}
+		if hit := engine.HitTest(root, 32, 32); hit != node {
+			t.Errorf("%s: expected to hit %s, got %v", step, node.Name(), hit)
+		}
+	}
+
+	expect("background", world)
+
+	top.SetLayer(engine.LayerHUD)
+	expect("layer changed", top)
+
+	top.SetInvisible()
+	expect("invisible", world)
+
+	// Moved into a HUD group that is first in the tree
+	top.SetVisible()
+	top.SetLayer(engine.LayerInherit)
+	hud := engine.NewGroupNode(nil, false)
+	hud.SetLayer(engine.LayerHUD)
+	root.InsertAt(0, hud)
+	hud.Add(top)
+	expect("added", top)
+
+	root.Remove(hud)
+	expect("removed", world)
+}
@@ -0,0 +1,180 @@
+package tests
+
+import (
+	"bytes"
+	"image"
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+	"github.com/wdevore/GameEngine/engine"
+)
+
+// steeringGame moves a node with the arrow keys and spins it with the mouse.
+type steeringGame struct {
+	node engine.INode
+}
+
+func (g *steeringGame) Update(dt float64, input *engine.Input) {
+	p := g.node.Position()
+	if input.IsKeyDown(sdl.SCANCODE_RIGHT) {
+		g.node.SetPositionBy2Comp(p.X+100.0*dt, p.Y)
+	}
+	if input.IsButtonPressed(engine.MouseButtonLeft) {
+		x, _ := input.MousePosition()
+		g.node.SetRotation(g.node.Rotation() + float64(x))
+	}
+}
+
+func (g *steeringGame) Render(pixels *image.RGBA, alpha float64) {
+}
+
+func newSteeringEngine() (*engine.Engine, *engine.HeadlessPlatform, *steeringGame) {
+	e, platform := newHeadlessEngine()
+	game := &steeringGame{node: engine.NewRectangleNode(e.GetRoot(), true, true)}
+	e.SetGame(game)
+	return e, platform, game
+}
+
+func Test_RecordAndReplay(t *testing.T) {
+	// Record a session
+	e, platform, game := newSteeringEngine()
+	e.Timestep().TickRate = 90.0
+
+	buf := new(bytes.Buffer)
+	if err := e.StartRecording(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	platform.PressKey(sdl.SCANCODE_RIGHT)
+	e.RunFrames(7)
+	platform.MoveMouse(3, 4)
+	platform.PressButton(engine.MouseButtonLeft)
+	platform.ReleaseKey(sdl.SCANCODE_RIGHT)
+	platform.TypeText("replay")
+	e.RunFrames(4)
+
+	if err := e.StopRecording(); err != nil {
+		t.Fatal(err)
+	}
+	e.Close()
+
+	replay, err := engine.ReadReplay(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replay.Len() != 11 {
+		t.Fatalf("Expected 11 frames, got %d", replay.Len())
+	}
+
+	// Play it back on a fresh engine with stray live input
+	re, rplatform, rgame := newSteeringEngine()
+	defer re.Close()
+
+	re.PlayReplay(replay)
+	rplatform.PressKey(sdl.SCANCODE_RIGHT)
+	re.RunFrames(replay.Len())
+
+	if re.IsReplaying() {
+		t.Error("Expected replay to be finished")
+	}
+	if re.Timestep().TickRate != 90.0 {
+		t.Errorf("Expected the recorded tick rate, got %f", re.Timestep().TickRate)
+	}
+
+	if !rgame.node.Position().Equal(game.node.Position()) {
+		t.Errorf("Expected position %v, got %v", game.node.Position(), rgame.node.Position())
+	}
+	if rgame.node.Rotation() != game.node.Rotation() || game.node.Rotation() != 3.0 {
+		t.Errorf("Expected rotation %f, got %f", game.node.Rotation(), rgame.node.Rotation())
+	}
+}
+
+func Test_ReadReplayRejectsGarbage(t *testing.T) {
+	_, err := engine.ReadReplay(bytes.NewBufferString("not a replay"))
+	if err != engine.ErrNotAReplay {
+		t.Errorf("Expected ErrNotAReplay, got %v", err)
+	}
+}
+
+func Test_ReplayStartedMidSession(t *testing.T) {
+	e, platform, game := newSteeringEngine()
+	defer e.Close()
+	e.Timestep().TickRate = 45.0
+
+	// Leave time in the accumulator and the key held as recording starts
+	platform.PressKey(sdl.SCANCODE_RIGHT)
+	e.RunFrames(5)
+	e.Clock().SetTimeScale(0.5)
+	start := game.node.Position().X
+
+	buf := new(bytes.Buffer)
+	if err := e.StartRecording(buf); err != nil {
+		t.Fatal(err)
+	}
+	e.RunFrames(10)
+	if err := e.StopRecording(); err != nil {
+		t.Fatal(err)
+	}
+
+	replay, err := engine.ReadReplay(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	re, _, rgame := newSteeringEngine()
+	defer re.Close()
+	rgame.node.SetPositionBy2Comp(start, 0.0)
+
+	re.PlayReplay(replay)
+	re.RunFrames(replay.Len())
+
+	if rgame.node.Position().X != game.node.Position().X {
+		t.Errorf("Expected x %f, got %f", game.node.Position().X, rgame.node.Position().X)
+	}
+	if re.Clock().TimeScale() != 0.5 {
+		t.Errorf("Expected the recorded time scale, got %f", re.Clock().TimeScale())
+	}
+}
+
+func Test_ReplayPlaysTwice(t *testing.T) {
+	e, platform, _ := newSteeringEngine()
+
+	buf := new(bytes.Buffer)
+	if err := e.StartRecording(buf); err != nil {
+		t.Fatal(err)
+	}
+	platform.PressKey(sdl.SCANCODE_A)
+	e.RunFrames(1)
+	platform.ReleaseKey(sdl.SCANCODE_A)
+	e.RunFrames(1)
+	if err := e.StopRecording(); err != nil {
+		t.Fatal(err)
+	}
+	e.Close()
+
+	replay, err := engine.ReadReplay(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	re, _, _ := newSteeringEngine()
+	defer re.Close()
+
+	keys := 0
+	re.Input().AddListener(engine.PriorityDefault, func(event engine.IEvent) {
+		if _, ok := event.(*engine.KeyEvent); ok {
+			keys++
+			event.Consume()
+		}
+	})
+
+	re.PlayReplay(replay)
+	re.RunFrames(replay.Len())
+	replay.Rewind()
+	re.PlayReplay(replay)
+	re.RunFrames(replay.Len())
+
+	if keys != 4 {
+		t.Errorf("Expected both key events on each playback, got %d", keys)
+	}
+}
@@ -0,0 +1,141 @@
+package tests
+
+import (
+	"image"
+	"testing"
+
+	"github.com/wdevore/GameEngine/engine"
+)
+
+const (
+	benchDepth    = 40
+	benchChildren = 100
+)
+
+// buildDeepTree creates a chain of groups benchDepth deep, each holding
+// benchChildren rectangles, 4000 rectangles in all. The deepest rectangle
+// is returned.
+func buildDeepTree() (engine.IGroupNode, engine.INode) {
+	return buildDeepTreeOf(func(parent engine.IGroupNode) engine.INode {
+		return engine.NewRectangleNode(parent, true, true)
+	})
+}
+
+// buildDeepTreeOf is buildDeepTree with the leaves made by newLeaf.
+func buildDeepTreeOf(newLeaf func(parent engine.IGroupNode) engine.INode) (engine.IGroupNode, engine.INode) {
+	root := engine.NewGroupNode(nil, false)
+
+	var leaf engine.INode
+	group := root
+	for d := 0; d < benchDepth; d++ {
+		group = engine.NewGroupNode(group, true)
+		group.SetPositionBy2Comp(1, 0)
+		group.SetRotationByDegree(1)
+
+		for c := 0; c < benchChildren; c++ {
+			leaf = newLeaf(group)
+			leaf.SetPositionBy2Comp(float64(c), 0)
+		}
+	}
+
+	return root, leaf
+}
+
+// Compares rendering a static tree, where every node reuses its cached
+// world transform, with one whose root changes every frame, which forces
+// every world transform to be recomputed.
+func Benchmark_RenderDeepTree(b *testing.B) {
+	// A tiny target keeps rasterization from swamping the transform cost.
+	context := engine.NewRenderContext(image.NewRGBA(image.Rect(0, 0, 4, 4)))
+
+	b.Run("static", func(b *testing.B) {
+		root, _ := buildDeepTree()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			root.Render(context)
+		}
+	})
+
+	b.Run("root-dirty", func(b *testing.B) {
+		root, _ := buildDeepTree()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			root.SetRotation(float64(i) * 0.001)
+			root.Render(context)
+		}
+	})
+}
+
+// Same as Benchmark_RenderDeepTree but with leaves that have no geometry
+// so only the traversal and transform costs are measured.
+func Benchmark_RenderDeepTreeTransformsOnly(b *testing.B) {
+	context := engine.NewRenderContext(image.NewRGBA(image.Rect(0, 0, 4, 4)))
+	newLeaf := func(parent engine.IGroupNode) engine.INode {
+		return engine.NewGroupNode(parent, true)
+	}
+
+	b.Run("static", func(b *testing.B) {
+		root, _ := buildDeepTreeOf(newLeaf)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			root.Render(context)
+		}
+	})
+
+	b.Run("root-dirty", func(b *testing.B) {
+		root, _ := buildDeepTreeOf(newLeaf)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			root.SetRotation(float64(i) * 0.001)
+			root.Render(context)
+		}
+	})
+}
+
+// Compares querying a deep leaf's world transform when nothing changed
+// with querying after an ancestor changed.
+func Benchmark_NodeToWorldDeepTree(b *testing.B) {
+	out := engine.NewVector3()
+	origin := engine.NewVector3()
+
+	b.Run("static", func(b *testing.B) {
+		_, leaf := buildDeepTree()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			leaf.NodeToWorld(origin, out)
+		}
+	})
+
+	b.Run("root-dirty", func(b *testing.B) {
+		root, leaf := buildDeepTree()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			root.SetRotation(float64(i) * 0.001)
+			leaf.NodeToWorld(origin, out)
+		}
+	})
+}
+
+func Test_RenderSharesWorldCache(t *testing.T) {
+	root, leaf := buildDeepTree()
+	context := engine.NewRenderContext(image.NewRGBA(image.Rect(0, 0, 4, 4)))
+
+	root.Render(context)
+	rendered := *leaf.NodeToWorldTransform()
+
+	root.SetPositionBy2Comp(10, 10)
+	root.Render(context)
+
+	moved := leaf.NodeToWorldTransform()
+	if engine.AffineTransformEqualToTransform(&rendered, moved) {
+		t.Error("Expected the leaf's world transform to follow the root")
+	}
+
+	expected := engine.NewVector3()
+	leaf.NodeToWorld(engine.NewVector3(), expected)
+	rootOnly := engine.NewVector3()
+	rendered.ApplyTo(engine.NewVector3(), rootOnly)
+	if !nearVector(expected, rootOnly.X+10, rootOnly.Y+10) {
+		t.Errorf("Expected leaf to move by <10, 10>, got %v from %v", expected, rootOnly)
+	}
+}
@@ -0,0 +1,178 @@
+package tests
+
+import (
+	"image/color"
+	"reflect"
+	"testing"
+
+	"github.com/wdevore/GameEngine/engine"
+)
+
+// loggingScene records its lifecycle calls.
+type loggingScene struct {
+	*engine.BaseScene
+	log *[]string
+}
+
+func newLoggingScene(name string, log *[]string, fill color.RGBA) *loggingScene {
+	s := &loggingScene{BaseScene: engine.NewBaseScene(name), log: log}
+
+	rect := engine.NewRectangleNode(s.Root(), false, true)
+	rect.SetScaleUniform(64)
+	rect.SetColor(fill)
+
+	return s
+}
+
+func (s *loggingScene) OnEnter() {
+	*s.log = append(*s.log, s.Name()+".enter")
+}
+
+func (s *loggingScene) OnExit() {
+	*s.log = append(*s.log, s.Name()+".exit")
+}
+
+func (s *loggingScene) OnEnterTransitionFinish() {
+	*s.log = append(*s.log, s.Name()+".finish")
+}
+
+var (
+	red  = color.RGBA{255, 0, 0, 255}
+	blue = color.RGBA{0, 0, 255, 255}
+)
+
+func Test_SceneStackLifecycle(t *testing.T) {
+	var log []string
+	m := engine.NewSceneManager()
+	a := newLoggingScene("A", &log, red)
+	b := newLoggingScene("B", &log, blue)
+
+	m.Push(a, nil)
+	m.Push(b, nil)
+	if m.Running() != b || m.Len() != 2 {
+		t.Errorf("Expected B on top of 2 scenes")
+	}
+
+	err := m.Pop(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Running() != a {
+		t.Errorf("Expected A running after pop")
+	}
+
+	m.Replace(b, nil)
+	if m.Running() != b || m.Len() != 1 {
+		t.Errorf("Expected only B after replace")
+	}
+
+	expected := []string{
+		"A.enter", "A.finish",
+		"A.exit", "B.enter", "B.finish",
+		"B.exit", "A.enter", "A.finish",
+		"A.exit", "B.enter", "B.finish",
+	}
+	if !reflect.DeepEqual(log, expected) {
+		t.Errorf("Expected %v, got %v", expected, log)
+	}
+
+	if err = m.Pop(nil); err != engine.ErrNoScene {
+		t.Errorf("Expected ErrNoScene popping the last scene, got %v", err)
+	}
+}
+
+func Test_SceneTransitionLifecycle(t *testing.T) {
+	e, _ := newHeadlessEngine()
+	defer e.Close()
+
+	var log []string
+	a := newLoggingScene("A", &log, red)
+	b := newLoggingScene("B", &log, blue)
+
+	e.Scenes().Push(a, nil)
+	e.Scenes().Push(b, engine.NewFadeTransition(0.5, color.RGBA{0, 0, 0, 255}))
+
+	// The incoming scene enters at the start, the outgoing exits at the end
+	expected := []string{"A.enter", "A.finish", "B.enter"}
+	if !reflect.DeepEqual(log, expected) {
+		t.Errorf("Expected %v, got %v", expected, log)
+	}
+	if e.GetRoot() != b.Root() {
+		t.Error("Expected the incoming scene to receive input during the transition")
+	}
+
+	e.RunFrames(35)
+
+	expected = append(expected, "A.exit", "B.finish")
+	if !reflect.DeepEqual(log, expected) {
+		t.Errorf("Expected %v, got %v", expected, log)
+	}
+	if e.Scenes().IsTransitioning() {
+		t.Error("Expected transition to have finished")
+	}
+	if c := e.Pixels().RGBAAt(32, 32); c != blue {
+		t.Errorf("Expected B to be shown, got %v", c)
+	}
+}
+
+// halfwayThrough runs a one second transition from a red scene to a blue
+// scene for half a second.
+func halfwayThrough(t *testing.T, transition engine.ITransition) *engine.Engine {
+	e, _ := newHeadlessEngine()
+
+	var log []string
+	e.Scenes().Push(newLoggingScene("A", &log, red), nil)
+	e.Scenes().Push(newLoggingScene("B", &log, blue), transition)
+
+	e.RunFrames(30)
+
+	if !e.Scenes().IsTransitioning() {
+		t.Fatal("Expected transition to be in progress")
+	}
+
+	return e
+}
+
+func Test_FadeTransition(t *testing.T) {
+	e := halfwayThrough(t, engine.NewFadeTransition(1.0, color.RGBA{0, 0, 0, 255}))
+	defer e.Close()
+
+	c := e.Pixels().RGBAAt(32, 32)
+	if c.R > 40 || c.B > 40 {
+		t.Errorf("Expected nearly black halfway through, got %v", c)
+	}
+}
+
+func Test_SlideTransition(t *testing.T) {
+	e := halfwayThrough(t, engine.NewSlideTransition(1.0, engine.TransitionLeft))
+	defer e.Close()
+
+	if c := e.Pixels().RGBAAt(10, 32); c != red {
+		t.Errorf("Expected outgoing scene on the left, got %v", c)
+	}
+	if c := e.Pixels().RGBAAt(54, 32); c != blue {
+		t.Errorf("Expected incoming scene on the right, got %v", c)
+	}
+}
+
+func Test_CrossDissolveTransition(t *testing.T) {
+	e := halfwayThrough(t, engine.NewCrossDissolveTransition(1.0))
+	defer e.Close()
+
+	c := e.Pixels().RGBAAt(32, 32)
+	if c.R < 100 || c.B < 100 || c.A != 255 {
+		t.Errorf("Expected a blend of both scenes, got %v", c)
+	}
+}
+
+func Test_WipeTransition(t *testing.T) {
+	e := halfwayThrough(t, engine.NewWipeTransition(1.0, engine.TransitionRight))
+	defer e.Close()
+
+	if c := e.Pixels().RGBAAt(10, 32); c != blue {
+		t.Errorf("Expected incoming scene revealed on the left, got %v", c)
+	}
+	if c := e.Pixels().RGBAAt(54, 32); c != red {
+		t.Errorf("Expected outgoing scene on the right, got %v", c)
+	}
+}
@@ -0,0 +1,196 @@
+package tests
+
+import (
+	"image/color"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/wdevore/GameEngine/engine"
+)
+
+// starNode is a user node type with its own property.
+type starNode struct {
+	engine.INode
+	points int
+}
+
+func (n *starNode) Properties() engine.Properties {
+	return engine.Properties{"points": n.points}
+}
+
+// bigNode is a user node type whose factory doesn't scale by 1.
+type bigNode struct {
+	engine.INode
+}
+
+func init() {
+	engine.RegisterNodeType("Big", &bigNode{}, func(parent engine.IGroupNode, props engine.Properties) (engine.INode, error) {
+		r := engine.NewRectangleNode(parent, false, false)
+		r.SetScaleUniform(10)
+		return &bigNode{INode: r}, nil
+	})
+
+	engine.RegisterNodeType("Star", &starNode{}, func(parent engine.IGroupNode, props engine.Properties) (engine.INode, error) {
+		points, err := props.Int("points", 5)
+		if err != nil {
+			return nil, err
+		}
+		return &starNode{INode: engine.NewRectangleNode(parent, false, false), points: points}, nil
+	})
+}
+
+func buildSerializableScene() engine.IGroupNode {
+	root := engine.NewGroupNode(nil, false)
+	root.SetName("Root")
+
+	wgroup := engine.NewGroupNode(root, true)
+	wgroup.SetName("whiteGroup")
+	wgroup.SetPositionBy2Comp(150, 150)
+
+	white := engine.NewRectangleNode(wgroup, true, true)
+	white.SetName("WhiteRect")
+	white.SetScaleUniform(25)
+	white.AddTag("player")
+
+	ogroup := engine.NewGroupNode(wgroup, true)
+	ogroup.SetName("orangeGroup")
+	ogroup.SetRotationByDegree(45)
+	ogroup.SetInvisible()
+	ogroup.SetLayer(engine.LayerHUD)
+	ogroup.SetOpacity(0.5)
+	ogroup.SetTint(color.RGBA{255, 0, 0, 255})
+
+	orange := engine.NewRectangleNode(ogroup, false, true)
+	orange.SetName("OrangeRect")
+	orange.SetColor(color.RGBA{255, 127, 0, 200})
+	orange.SetPositionBy2Comp(50, 0)
+
+	star := &starNode{INode: engine.NewRectangleNode(root, true, false), points: 5}
+	star.SetName("Star")
+	star.SetZIndex(1)
+	root.Add(star)
+
+	return root
+}
+
+func Test_SceneJSONRoundTrip(t *testing.T) {
+	root := buildSerializableScene()
+
+	data, err := engine.MarshalSceneJSON(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := engine.UnmarshalSceneJSON(data, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, _ := engine.DescribeNode(root)
+	actual, err := engine.DescribeNode(loaded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Properties decode with JSON types so compare them separately.
+	expected.Children[1].Properties = nil
+	star := actual.Children[1]
+	if star.Type != "Star" || star.Properties["points"] != 5 {
+		t.Errorf("Expected a 5 point star, got %v", star)
+	}
+	star.Properties = nil
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expected %s, got %+v", data, actual)
+	}
+}
+
+func Test_SceneFileRoundTrip(t *testing.T) {
+	root := buildSerializableScene()
+
+	for _, name := range []string{"scene.json", "scene.yaml"} {
+		path := filepath.Join(t.TempDir(), name)
+		if err := engine.SaveScene(path, root); err != nil {
+			t.Fatal(err)
+		}
+
+		parent := engine.NewGroupNode(nil, false)
+		loaded, err := engine.LoadScene(path, parent)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, found := parent.Find(loaded); found == nil {
+			t.Errorf("%s: expected scene added to parent", name)
+		}
+
+		d, _ := engine.DescribeNode(loaded)
+		orange := d.Children[0].Children[1].Children[0]
+		if orange.Name != "OrangeRect" || orange.Color != "#ff7f00c8" || orange.Position.X != 50 {
+			t.Errorf("%s: unexpected orange rect %+v", name, orange)
+		}
+		if *d.Children[0].Children[1].Visible {
+			t.Errorf("%s: expected orangeGroup to be invisible", name)
+		}
+	}
+}
+
+func Test_SceneUnregisteredType(t *testing.T) {
+	root := engine.NewGroupNode(nil, false)
+	root.Add(&updateCounter{INode: engine.NewRectangleNode(root, true, false)})
+
+	if _, err := engine.MarshalSceneJSON(root); err == nil {
+		t.Error("Expected an unregistered type to fail")
+	}
+
+	if _, err := engine.UnmarshalSceneJSON([]byte(`{"type": "Nope"}`), nil); err == nil {
+		t.Error("Expected an unknown type to fail")
+	}
+}
+
+func Test_SceneDefaultsForMissingFields(t *testing.T) {
+	json := `{"type": "Group", "name": "g", "timeScale": 0.5,
+		"children": [{"type": "Rectangle", "name": "r", "position": {"x": 5, "y": 6}}]}`
+	yaml := "type: Group\nname: g\ntimeScale: 0.5\nchildren:\n  - type: Rectangle\n    name: r\n    position: {x: 5, y: 6}\n"
+
+	fromJSON, err := engine.UnmarshalSceneJSON([]byte(json), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromYAML, err := engine.UnmarshalSceneYAML([]byte(yaml), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	big, err := engine.UnmarshalSceneJSON([]byte(`{"type": "Big"}`), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if big.Scale().X != 1.0 || big.Scale().Y != 1.0 {
+		t.Errorf("Expected a missing scale to be 1, got %v", big.Scale())
+	}
+
+	for name, n := range map[string]engine.INode{"json": fromJSON, "yaml": fromYAML} {
+		g := n.(engine.IGroupNode)
+		r := g.Children()[0]
+		if !r.IsVisible() || r.Scale().X != 1.0 || r.Scale().Y != 1.0 {
+			t.Errorf("%s: expected visible at scale 1, got %v %v", name, r.IsVisible(), r.Scale())
+		}
+		if g.TimeScale() != 0.5 {
+			t.Errorf("%s: expected time scale 0.5, got %f", name, g.TimeScale())
+		}
+	}
+}
+
+func Test_CloneKeepsTimeScale(t *testing.T) {
+	g := engine.NewGroupNode(nil, false)
+	g.SetTimeScale(0.25)
+
+	c, err := engine.Clone(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.(engine.IGroupNode).TimeScale() != 0.25 {
+		t.Errorf("Expected the time scale cloned, got %f", c.(engine.IGroupNode).TimeScale())
+	}
+}
@@ -0,0 +1,136 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/wdevore/GameEngine/engine"
+)
+
+func Test_ScheduleTimers(t *testing.T) {
+	e, _ := newHeadlessEngine()
+	defer e.Close()
+
+	n := engine.NewRectangleNode(e.GetRoot(), false, true)
+
+	frames := 0
+	n.Schedule(func(dt float64) {
+		frames++
+	})
+
+	once := 0
+	onceTimer := n.ScheduleOnce(0.5, func() {
+		once++
+	})
+
+	ticks := 0
+	n.ScheduleInterval(0.1, 3, func(dt float64) {
+		ticks++
+	})
+
+	e.RunFrames(60)
+
+	if frames != 60 {
+		t.Errorf("Expected 60 per-frame calls, got %d", frames)
+	}
+	if once != 1 || onceTimer.IsActive() {
+		t.Errorf("Expected one-shot to fire once, got %d", once)
+	}
+	if ticks != 3 {
+		t.Errorf("Expected 3 interval calls, got %d", ticks)
+	}
+}
+
+func Test_TimersFollowPauseAndTimeScale(t *testing.T) {
+	e, _ := newHeadlessEngine()
+	defer e.Close()
+
+	slow := engine.NewGroupNode(e.GetRoot(), true)
+	slow.SetTimeScale(0.5)
+	n := engine.NewRectangleNode(slow, false, true)
+
+	elapsed := 0.0
+	n.Schedule(func(dt float64) {
+		elapsed += dt
+	})
+
+	e.RunFrames(60)
+	if !near(elapsed, 0.5) {
+		t.Errorf("Expected half a second at half speed, got %f", elapsed)
+	}
+
+	e.Clock().Pause()
+	e.RunFrames(60)
+	if !near(elapsed, 0.5) {
+		t.Errorf("Expected no time while paused, got %f", elapsed)
+	}
+}
+
+func Test_TimersCancelledOnExit(t *testing.T) {
+	e, _ := newHeadlessEngine()
+	defer e.Close()
+
+	n := engine.NewRectangleNode(e.GetRoot(), false, true)
+
+	calls := 0
+	timer := n.ScheduleInterval(0.1, engine.RepeatForever, func(dt float64) {
+		calls++
+		if calls == 2 {
+			// Removing the node from its own timer is safe
+			e.GetRoot().Remove(n)
+		}
+	})
+
+	e.RunFrames(60)
+
+	if calls != 2 {
+		t.Errorf("Expected timer to stop once the node left, got %d calls", calls)
+	}
+	if timer.IsActive() {
+		t.Error("Expected timer to be cancelled")
+	}
+
+	// A timer cancelled before it fires never does
+	e.GetRoot().Add(n)
+	calls = 0
+	n.Schedule(func(dt float64) {
+		calls++
+	}).Cancel()
+	e.RunFrames(5)
+	if calls != 0 {
+		t.Errorf("Expected a cancelled timer not to fire, got %d", calls)
+	}
+}
+
+func Test_TimerRemovesNodesDuringUpdate(t *testing.T) {
+	e, _ := newHeadlessEngine()
+	defer e.Close()
+
+	root := e.GetRoot()
+	a := engine.NewRectangleNode(root, false, true)
+	b := engine.NewRectangleNode(root, false, true)
+	c := engine.NewRectangleNode(root, false, true)
+
+	// A node removing itself, the last child, and an action removing a
+	// sibling later in the same update
+	c.ScheduleOnce(0.1, func() {
+		root.Remove(c)
+	})
+	e.Actions().Run(a, engine.NewSequence(
+		engine.NewDelay(0.2),
+		engine.NewCallFunc(func(engine.INode) { root.Remove(b) }),
+	), "")
+
+	bTicks := 0
+	b.Schedule(func(float64) {
+		bTicks++
+	})
+
+	e.RunFrames(30)
+
+	if len(root.Children()) != 1 || c.Parent() != nil || b.Parent() != nil {
+		t.Errorf("Expected only the first node left, got %d", len(root.Children()))
+	}
+	if bTicks > 13 {
+		t.Errorf("Expected the removed node to stop ticking, got %d", bTicks)
+	}
+}
@@ -0,0 +1,86 @@
+package tests
+
+import (
+	"math"
+	"testing"
+
+	"github.com/wdevore/GameEngine/engine"
+)
+
+func Test_TimestepAccumulates(t *testing.T) {
+	ts := engine.NewFixedTimestep(60.0)
+	period := ts.StepPeriod()
+
+	steps := 0
+	alpha := ts.Advance(period*2.5, func(dt float64) {
+		steps++
+		if dt != period {
+			t.Errorf("Expected dt %f, got %f", period, dt)
+		}
+	})
+
+	if steps != 2 {
+		t.Errorf("Expected 2 steps, got %d", steps)
+	}
+	if math.Abs(alpha-0.5) > engine.Epsilon {
+		t.Errorf("Expected alpha 0.5, got %f", alpha)
+	}
+
+	// The left over half step plus a bit more than another half makes
+	// one more step.
+	steps = 0
+	ts.Advance(period*0.6, func(dt float64) { steps++ })
+	if steps != 1 {
+		t.Errorf("Expected 1 step, got %d", steps)
+	}
+}
+
+func Test_TimestepCatchUpGuard(t *testing.T) {
+	ts := engine.NewFixedTimestep(100.0)
+	ts.MaxCatchUpSteps = 3
+	ts.MaxFrameTime = 1.0
+
+	steps := 0
+	alpha := ts.Advance(0.105, func(dt float64) { steps++ })
+
+	if steps != 3 {
+		t.Errorf("Expected 3 steps, got %d", steps)
+	}
+	if ts.Dropped() != 7 {
+		t.Errorf("Expected 7 dropped steps, got %d", ts.Dropped())
+	}
+	if alpha < 0.0 || alpha >= 1.0 {
+		t.Errorf("Expected alpha in [0, 1), got %f", alpha)
+	}
+}
+
+func Test_TimestepClampsFrameTime(t *testing.T) {
+	ts := engine.NewFixedTimestep(10.0)
+	ts.MaxCatchUpSteps = 100
+	ts.MaxFrameTime = 0.25
+
+	steps := 0
+	ts.Advance(5.0, func(dt float64) { steps++ })
+
+	if steps != 2 {
+		t.Errorf("Expected 2 steps, got %d", steps)
+	}
+}
+
+func Test_HeadlessFixedStep(t *testing.T) {
+	e, _ := newHeadlessEngine()
+	defer e.Close()
+
+	e.Timestep().TickRate = 120.0
+
+	game := new(recordingGame)
+	e.SetGame(game)
+	e.RunFrames(3)
+
+	if game.updates != 6 {
+		t.Errorf("Expected 6 updates, got %d", game.updates)
+	}
+	if game.dt != 1.0/120.0 {
+		t.Errorf("Expected dt %f, got %f", 1.0/120.0, game.dt)
+	}
+}
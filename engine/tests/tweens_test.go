@@ -0,0 +1,195 @@
+package tests
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/wdevore/GameEngine/engine"
+)
+
+func Test_TweenDelayRepeatYoyo(t *testing.T) {
+	value := 0.0
+	tw := engine.NewFloatTween(&value, 0.0, 10.0, 1.0)
+	tw.SetDelay(0.5)
+	tw.SetRepeat(2)
+	tw.SetYoyo(true)
+
+	started, completed := 0, 0
+	var plays []int
+	tw.OnStart(func() { started++ })
+	tw.OnRepeat(func(play int) { plays = append(plays, play) })
+	tw.OnComplete(func() { completed++ })
+
+	if tw.Duration() != 3.5 {
+		t.Errorf("Expected a delay and 3 plays, got %f", tw.Duration())
+	}
+
+	tw.Update(0.25)
+	if value != 0.0 || started != 0 {
+		t.Errorf("Expected nothing during the delay, got %f", value)
+	}
+
+	tw.Update(0.5)
+	if !near(value, 2.5) || started != 1 {
+		t.Errorf("Expected a quarter way, got %f", value)
+	}
+
+	// The second play runs backwards
+	tw.Update(1.0)
+	if !near(value, 7.5) || len(plays) != 1 {
+		t.Errorf("Expected to be heading back at 7.5, got %f", value)
+	}
+
+	tw.Update(5.0)
+	if !near(value, 10.0) || !tw.IsComplete() || completed != 1 {
+		t.Errorf("Expected complete at 10, got %f", value)
+	}
+	if len(plays) != 2 || plays[1] != 2 {
+		t.Errorf("Expected 2 repeats, got %v", plays)
+	}
+
+	// Seeking back before the start restores the start value
+	tw.Seek(0.0)
+	if value != 0.0 || tw.IsComplete() {
+		t.Errorf("Expected rewound to 0, got %f", value)
+	}
+}
+
+func Test_TweenTargets(t *testing.T) {
+	v := engine.NewVector3()
+	vt := engine.NewVectorTween(v, engine.NewVector3With2Components(0.0, 10.0), engine.NewVector3With2Components(10.0, 20.0), 2.0)
+	vt.SetEasing(engine.EaseInQuad)
+	vt.Seek(1.0)
+	if !nearVector(v, 2.5, 12.5) {
+		t.Errorf("Expected eased 2.5,12.5, got %f,%f", v.X, v.Y)
+	}
+
+	var c color.RGBA
+	ct := engine.NewColorTween(&c, color.RGBA{0, 0, 0, 255}, color.RGBA{200, 100, 0, 255}, 1.0)
+	ct.Seek(0.5)
+	if c != (color.RGBA{100, 50, 0, 255}) {
+		t.Errorf("Expected half way color, got %v", c)
+	}
+
+	// Tweening a node property through its setter
+	n := engine.NewRectangleNode(engine.NewGroupNode(nil, false), false, true)
+	ft := engine.NewTweenFunc(1.0, 0.0, 1.0, n.SetOpacity)
+	ft.Seek(0.75)
+	if !near(n.Opacity(), 0.25) {
+		t.Errorf("Expected opacity 0.25, got %f", n.Opacity())
+	}
+
+	forever := engine.NewTweenFunc(0.0, 1.0, 1.0, func(float64) {})
+	forever.SetRepeat(engine.RepeatForever)
+	if !math.IsInf(forever.Duration(), 1) {
+		t.Errorf("Expected a forever tween to last forever")
+	}
+}
+
+func Test_TimelineSequenceOverlapAndLabels(t *testing.T) {
+	x, y := 0.0, 0.0
+
+	tl := engine.NewTimeline()
+	tl.Add(engine.NewFloatTween(&x, 0.0, 10.0, 1.0))
+	tl.AddLabel("second")
+	tl.Add(engine.NewFloatTween(&x, 10.0, 20.0, 1.0))
+	// y overlaps the last half of the first x tween
+	tl.AddAt(engine.NewFloatTween(&y, 0.0, 1.0, 1.0), 0.5)
+
+	err := tl.AddAtLabel(engine.NewFloatTween(&y, 1.0, 5.0, 1.0), "second", 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tl.AddAtLabel(nil, "missing", 0.0) == nil {
+		t.Errorf("Expected an error for an unknown label")
+	}
+
+	if tl.Duration() != 2.5 {
+		t.Errorf("Expected 2.5s, got %f", tl.Duration())
+	}
+
+	completed := 0
+	tl.OnComplete(func() { completed++ })
+
+	tl.Update(0.75)
+	if !near(x, 7.5) || !near(y, 0.25) {
+		t.Errorf("Expected overlapped tweens at 7.5, 0.25, got %f, %f", x, y)
+	}
+
+	err = tl.SeekLabel("second")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !near(x, 10.0) || !near(y, 0.5) {
+		t.Errorf("Expected 10, 0.5 at the label, got %f, %f", x, y)
+	}
+
+	tl.Seek(10.0)
+	if !near(x, 20.0) || !near(y, 5.0) || completed != 1 {
+		t.Errorf("Expected the end values, got %f, %f", x, y)
+	}
+
+	// Seeking back lets the earliest tween of a shared target win
+	tl.Seek(0.25)
+	if !near(x, 2.5) || !near(y, 0.0) || tl.IsComplete() {
+		t.Errorf("Expected rewound to 2.5, 0, got %f, %f", x, y)
+	}
+}
+
+func Test_TimelineRunsAsAction(t *testing.T) {
+	e, _ := newHeadlessEngine()
+	defer e.Close()
+
+	n := engine.NewRectangleNode(e.GetRoot(), false, true)
+
+	inner := engine.NewTimeline()
+	inner.Add(engine.NewTweenFunc(0.0, 100.0, 0.5, func(v float64) {
+		n.SetPositionBy2Comp(v, 0.0)
+	}))
+
+	tl := engine.NewTimeline()
+	tl.Add(inner)
+	tl.Add(engine.NewTweenFunc(1.0, 0.0, 0.5, n.SetOpacity))
+
+	r := e.Actions().Run(n, tl, "intro")
+	e.RunFrames(45)
+	if !near(n.Position().X, 100.0) || !near(n.Opacity(), 0.5) {
+		t.Errorf("Expected moved and half faded, got %f, %f", n.Position().X, n.Opacity())
+	}
+
+	e.RunFrames(30)
+	if !r.IsDone() || !tl.IsComplete() || n.Opacity() != 0.0 {
+		t.Errorf("Expected the timeline to finish")
+	}
+}
+
+func Test_ParticleRetriggerReusesTween(t *testing.T) {
+	trigger := func(p *engine.Particle, s *engine.ParticleSystem) {
+		p.StartColor = color.RGBA{0, 0, 0, 255}
+		p.EndColor = color.RGBA{100, 200, 0, 255}
+		p.Duration = 2.0
+	}
+	ps := engine.NewParticleSystem(1, func(*engine.Particle, *image.RGBA) {}, trigger)
+
+	allocs := testing.AllocsPerRun(10, func() {
+		ps.TriggerParticle()
+		ps.Update(1.0)
+		ps.Update(1.0)
+		ps.Update(1.0)
+	})
+	if allocs != 0 {
+		t.Errorf("Expected retriggering not to allocate, got %f", allocs)
+	}
+
+	var seen color.RGBA
+	ps = engine.NewParticleSystem(1, func(p *engine.Particle, _ *image.RGBA) { seen = p.RenderColor }, trigger)
+	ps.TriggerParticle()
+	ps.Update(1.0)
+	ps.Update(0.5)
+	ps.Render(nil)
+	if seen != (color.RGBA{50, 100, 0, 255}) {
+		t.Errorf("Expected half way color, got %v", seen)
+	}
+}
@@ -0,0 +1,95 @@
+package tests
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/wdevore/GameEngine/engine"
+)
+
+func walkNames(root engine.INode, order engine.WalkOrder, visit engine.Visitor) ([]string, bool) {
+	var visited []string
+	completed := engine.Walk(root, order, func(n engine.INode, depth int) engine.WalkAction {
+		visited = append(visited, n.Name())
+		if visit != nil {
+			return visit(n, depth)
+		}
+		return engine.WalkContinue
+	})
+	return visited, completed
+}
+
+func Test_WalkOrders(t *testing.T) {
+	root := buildQueryScene()
+
+	cases := []struct {
+		order    engine.WalkOrder
+		expected []string
+	}{
+		{engine.WalkPreOrder, []string{"Root", "whiteGroup", "WhiteRect", "orangeGroup", "OrangeRect", "OrangeRing", "OrangeRect"}},
+		{engine.WalkPostOrder, []string{"WhiteRect", "OrangeRect", "OrangeRing", "orangeGroup", "whiteGroup", "OrangeRect", "Root"}},
+		{engine.WalkBreadthFirst, []string{"Root", "whiteGroup", "OrangeRect", "WhiteRect", "orangeGroup", "OrangeRect", "OrangeRing"}},
+	}
+
+	for _, c := range cases {
+		visited, completed := walkNames(root, c.order, nil)
+		if !completed || !reflect.DeepEqual(visited, c.expected) {
+			t.Errorf("Order %d: expected %v, got %v", c.order, c.expected, visited)
+		}
+	}
+}
+
+func Test_WalkPruneAndStop(t *testing.T) {
+	root := buildQueryScene()
+
+	prune := func(n engine.INode, depth int) engine.WalkAction {
+		if n.Name() == "orangeGroup" {
+			return engine.WalkPrune
+		}
+		return engine.WalkContinue
+	}
+
+	for _, order := range []engine.WalkOrder{engine.WalkPreOrder, engine.WalkBreadthFirst} {
+		visited, _ := walkNames(root, order, prune)
+		if len(visited) != 5 || strings.Contains(strings.Join(visited, ","), "OrangeRing") {
+			t.Errorf("Order %d: expected orangeGroup's children pruned, got %v", order, visited)
+		}
+	}
+
+	stop := func(n engine.INode, depth int) engine.WalkAction {
+		if depth == 2 {
+			return engine.WalkStop
+		}
+		return engine.WalkContinue
+	}
+
+	visited, completed := walkNames(root, engine.WalkPreOrder, stop)
+	if completed || !reflect.DeepEqual(visited, []string{"Root", "whiteGroup", "WhiteRect"}) {
+		t.Errorf("Expected walk to stop at the first depth 2 node, got %v", visited)
+	}
+
+	visited, completed = walkNames(root, engine.WalkPostOrder, stop)
+	if completed || len(visited) != 1 {
+		t.Errorf("Expected post-order walk to stop at the first node, got %v", visited)
+	}
+}
+
+func Test_DumpTree(t *testing.T) {
+	root := buildQueryScene()
+
+	var b bytes.Buffer
+	err := engine.DumpTree(&b, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if len(lines) != 7 {
+		t.Fatalf("Expected 7 lines, got %d:\n%s", len(lines), b.String())
+	}
+	if !strings.HasPrefix(lines[4], "      Rectangle 'OrangeRect'") || !strings.HasSuffix(lines[4], "@shape @enemy") {
+		t.Errorf("Unexpected line: %s", lines[4])
+	}
+}
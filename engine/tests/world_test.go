@@ -0,0 +1,89 @@
+package tests
+
+import (
+	"math"
+	"testing"
+
+	"github.com/wdevore/GameEngine/engine"
+)
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < engine.Epsilon
+}
+
+func nearVector(v *engine.Vector3, x, y float64) bool {
+	return near(v.X, x) && near(v.Y, y)
+}
+
+func Test_NodeToWorld(t *testing.T) {
+	root := engine.NewGroupNode(nil, false)
+
+	group := engine.NewGroupNode(root, true)
+	group.SetPositionBy2Comp(100, 50)
+	group.SetScaleUniform(2)
+
+	child := engine.NewRectangleNode(group, true, true)
+	child.SetPositionBy2Comp(10, 0)
+
+	out := engine.NewVector3()
+	child.NodeToWorld(engine.NewVector3With2Components(1, 1), out)
+	if !nearVector(out, 122, 52) {
+		t.Errorf("Expected <122, 52>, got %v", out)
+	}
+
+	child.WorldToNode(out, out)
+	if !nearVector(out, 1, 1) {
+		t.Errorf("Expected round trip <1, 1>, got %v", out)
+	}
+}
+
+func Test_WorldCacheInvalidatedByAncestor(t *testing.T) {
+	root := engine.NewGroupNode(nil, false)
+	outer := engine.NewGroupNode(root, true)
+	inner := engine.NewGroupNode(outer, true)
+	leaf := engine.NewRectangleNode(inner, true, true)
+	leaf.SetPositionBy2Comp(5, 0)
+
+	origin := engine.NewVector3()
+	out := engine.NewVector3()
+
+	leaf.NodeToWorld(origin, out)
+	if !nearVector(out, 5, 0) {
+		t.Errorf("Expected <5, 0>, got %v", out)
+	}
+
+	// Changing the grandparent must invalidate the leaf's cached transforms.
+	outer.SetPositionBy2Comp(0, 30)
+	leaf.NodeToWorld(origin, out)
+	if !nearVector(out, 5, 30) {
+		t.Errorf("Expected <5, 30>, got %v", out)
+	}
+
+	outer.SetRotationByDegree(90)
+	leaf.NodeToWorld(origin, out)
+	if !nearVector(out, 0, 35) {
+		t.Errorf("Expected <0, 35>, got %v", out)
+	}
+
+	leaf.WorldToNode(engine.NewVector3With2Components(0, 35), out)
+	if !nearVector(out, 0, 0) {
+		t.Errorf("Expected inverse to follow, got %v", out)
+	}
+}
+
+func Test_NodeToNode(t *testing.T) {
+	root := engine.NewGroupNode(nil, false)
+
+	a := engine.NewRectangleNode(root, true, true)
+	a.SetPositionBy2Comp(10, 10)
+
+	b := engine.NewRectangleNode(root, true, true)
+	b.SetPositionBy2Comp(20, 10)
+	b.SetScaleUniform(2)
+
+	out := engine.NewVector3()
+	a.NodeToNode(engine.NewVector3With2Components(0, 0), b, out)
+	if !nearVector(out, -5, 0) {
+		t.Errorf("Expected <-5, 0>, got %v", out)
+	}
+}
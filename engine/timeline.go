@@ -0,0 +1,170 @@
+package engine
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Timeline plays tweens, and other timelines, at set times so they can
+// run in sequence or overlap. Named labels mark times to add tweens at or
+// seek to.
+type Timeline struct {
+	// Sorted by start time
+	entries []timelineEntry
+	labels  map[string]float64
+	// Where Add places the next tween
+	end float64
+
+	onComplete func()
+
+	time      float64
+	completed bool
+}
+
+type timelineEntry struct {
+	start float64
+	tween ITween
+}
+
+// NewTimeline creates an empty timeline.
+func NewTimeline() *Timeline {
+	tl := new(Timeline)
+	tl.labels = map[string]float64{}
+	return tl
+}
+
+// Add places tween after everything added so far.
+func (tl *Timeline) Add(tween ITween) {
+	tl.AddAt(tween, tl.end)
+}
+
+// AddAt places tween at time seconds from the start. Times before the
+// end of the timeline overlap earlier tweens.
+func (tl *Timeline) AddAt(tween ITween, time float64) {
+	time = math.Max(time, 0.0)
+	tween.Restart()
+
+	tl.entries = append(tl.entries, timelineEntry{start: time, tween: tween})
+	sort.SliceStable(tl.entries, func(i, j int) bool {
+		return tl.entries[i].start < tl.entries[j].start
+	})
+
+	tl.end = math.Max(tl.end, time+tween.Duration())
+}
+
+// AddAtLabel places tween offset seconds after label.
+func (tl *Timeline) AddAtLabel(tween ITween, label string, offset float64) error {
+	time, ok := tl.labels[label]
+	if !ok {
+		return fmt.Errorf("unknown timeline label '%s'", label)
+	}
+	tl.AddAt(tween, time+offset)
+	return nil
+}
+
+// AddLabel names the end of the timeline as it is now.
+func (tl *Timeline) AddLabel(label string) {
+	tl.labels[label] = tl.end
+}
+
+// AddLabelAt names time.
+func (tl *Timeline) AddLabelAt(label string, time float64) {
+	tl.labels[label] = time
+}
+
+// Label returns the time of label.
+func (tl *Timeline) Label(label string) (float64, bool) {
+	time, ok := tl.labels[label]
+	return time, ok
+}
+
+// OnComplete sets a callback for when the timeline reaches its end.
+func (tl *Timeline) OnComplete(callback func()) {
+	tl.onComplete = callback
+}
+
+// Duration returns the time the last tween finishes.
+func (tl *Timeline) Duration() float64 {
+	return tl.end
+}
+
+// Time returns the time since the start.
+func (tl *Timeline) Time() float64 {
+	return tl.time
+}
+
+// IsComplete is true once the end has been reached.
+func (tl *Timeline) IsComplete() bool {
+	return tl.completed
+}
+
+// Restart rewinds the timeline and its tweens.
+func (tl *Timeline) Restart() {
+	tl.time = 0.0
+	tl.completed = false
+	for _, e := range tl.entries {
+		e.tween.Restart()
+	}
+}
+
+// Update advances the timeline by dt seconds.
+func (tl *Timeline) Update(dt float64) {
+	if tl.completed {
+		return
+	}
+	tl.Seek(tl.time + dt)
+}
+
+// SeekLabel positions the timeline at label.
+func (tl *Timeline) SeekLabel(label string) error {
+	time, ok := tl.labels[label]
+	if !ok {
+		return fmt.Errorf("unknown timeline label '%s'", label)
+	}
+	tl.Seek(time)
+	return nil
+}
+
+// Seek positions the timeline time seconds from its start. Only tweens
+// whose span the move crosses are touched: in start order moving forward
+// and in reverse moving back, so where tweens share a target the right
+// one has the last word.
+func (tl *Timeline) Seek(time float64) {
+	time = math.Max(0.0, math.Min(time, tl.end))
+	from := tl.time
+	tl.time = time
+
+	if time >= from {
+		for _, e := range tl.entries {
+			if e.start <= time && e.start+e.tween.Duration() >= from {
+				e.tween.Seek(time - e.start)
+			}
+		}
+	} else {
+		for i := len(tl.entries) - 1; i >= 0; i-- {
+			e := tl.entries[i]
+			if e.start <= from && e.start+e.tween.Duration() >= time {
+				e.tween.Seek(time - e.start)
+			}
+		}
+	}
+
+	if time < tl.end {
+		tl.completed = false
+	} else if !tl.completed {
+		tl.completed = true
+		if time >= from && tl.onComplete != nil {
+			tl.onComplete()
+		}
+	}
+}
+
+// start and update run the timeline as an action, the node is ignored.
+func (tl *Timeline) start(target INode) {
+	tl.Restart()
+}
+
+func (tl *Timeline) update(elapsed float64) {
+	tl.Seek(elapsed)
+}
@@ -0,0 +1,105 @@
+package engine
+
+import "math"
+
+const (
+	// DefaultTickRate is the number of simulation steps per second.
+	DefaultTickRate = 60.0
+
+	// DefaultMaxCatchUpSteps is how many simulation steps a single frame
+	// may run before the backlog is dropped.
+	DefaultMaxCatchUpSteps = 5
+
+	// DefaultMaxFrameTime (seconds) clamps a frame's elapsed time, for
+	// example, after the process was suspended in a debugger.
+	DefaultMaxFrameTime = 0.25
+)
+
+// FixedTimestep accumulates real frame time and converts it into a whole
+// number of fixed size simulation steps. Whatever time is left over is
+// reported as an interpolation alpha in the range [0, 1) so rendering can
+// blend between the previous and current simulation states.
+type FixedTimestep struct {
+	// TickRate is the number of simulation steps per second.
+	TickRate float64
+
+	// MaxCatchUpSteps caps how many steps a single frame may run. Any
+	// remaining whole steps are dropped, which guards against the
+	// "spiral of death" where updates can never catch up with real time.
+	MaxCatchUpSteps int
+
+	// MaxFrameTime (seconds) clamps the time fed into the accumulator.
+	MaxFrameTime float64
+
+	accumulator float64
+	alpha       float64
+
+	// Number of steps dropped by the catch up guard
+	dropped int
+}
+
+// NewFixedTimestep creates a timestep running tickRate steps per second.
+func NewFixedTimestep(tickRate float64) *FixedTimestep {
+	ts := new(FixedTimestep)
+	ts.TickRate = tickRate
+	ts.MaxCatchUpSteps = DefaultMaxCatchUpSteps
+	ts.MaxFrameTime = DefaultMaxFrameTime
+	return ts
+}
+
+// StepPeriod returns the fixed step size in seconds.
+func (ts *FixedTimestep) StepPeriod() float64 {
+	return 1.0 / ts.TickRate
+}
+
+// Advance adds frameTime (seconds) to the accumulator and calls step once
+// for each whole step period available, passing the step period as dt.
+// The interpolation alpha is returned.
+func (ts *FixedTimestep) Advance(frameTime float64, step func(dt float64)) float64 {
+	if frameTime < 0.0 {
+		frameTime = 0.0
+	}
+	if frameTime > ts.MaxFrameTime {
+		frameTime = ts.MaxFrameTime
+	}
+
+	ts.accumulator += frameTime
+
+	period := ts.StepPeriod()
+	steps := 0
+
+	for ts.accumulator >= period {
+		if steps >= ts.MaxCatchUpSteps {
+			// Drop the backlog but keep the fractional remainder so
+			// the alpha remains meaningful.
+			ts.dropped += int(ts.accumulator / period)
+			ts.accumulator = math.Mod(ts.accumulator, period)
+			break
+		}
+
+		step(period)
+
+		ts.accumulator -= period
+		steps++
+	}
+
+	ts.alpha = ts.accumulator / period
+
+	return ts.alpha
+}
+
+// Alpha returns the interpolation alpha computed by the last Advance.
+func (ts *FixedTimestep) Alpha() float64 {
+	return ts.alpha
+}
+
+// Dropped returns how many steps have been dropped by the catch up guard.
+func (ts *FixedTimestep) Dropped() int {
+	return ts.dropped
+}
+
+// Reset clears any accumulated time.
+func (ts *FixedTimestep) Reset() {
+	ts.accumulator = 0.0
+	ts.alpha = 0.0
+}
@@ -0,0 +1,241 @@
+package engine
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// ITransition animates the change from one scene to another.
+type ITransition interface {
+	// Duration in seconds
+	Duration() float64
+	// Render draws the scenes t, [0, 1], of the way through the
+	// transition. from is nil when there was no running scene.
+	Render(context *RenderContext, from, to IScene, t float64)
+}
+
+// TransitionDirection is the direction the incoming scene moves or is
+// revealed in.
+type TransitionDirection int
+
+const (
+	// TransitionLeft brings the incoming scene in from the right
+	TransitionLeft TransitionDirection = iota
+	// TransitionRight brings the incoming scene in from the left
+	TransitionRight
+	// TransitionUp brings the incoming scene in from the bottom
+	TransitionUp
+	// TransitionDown brings the incoming scene in from the top
+	TransitionDown
+)
+
+func renderScene(context *RenderContext, scene IScene) {
+	if scene != nil {
+		renderTree(context, scene.Root())
+	}
+}
+
+// -----------------------------------------------------------------
+// Fade
+// -----------------------------------------------------------------
+
+// FadeTransition fades the outgoing scene out to a color and then the
+// incoming scene in from it.
+type FadeTransition struct {
+	duration float64
+	color    color.RGBA
+}
+
+// NewFadeTransition creates a fade through color, typically black.
+func NewFadeTransition(duration float64, color color.RGBA) *FadeTransition {
+	t := new(FadeTransition)
+	t.duration = duration
+	t.color = color
+	return t
+}
+
+func (ft *FadeTransition) Duration() float64 {
+	return ft.duration
+}
+
+func (ft *FadeTransition) Render(context *RenderContext, from, to IScene, t float64) {
+	var opacity float64
+	if t < 0.5 {
+		renderScene(context, from)
+		opacity = t * 2.0
+	} else {
+		renderScene(context, to)
+		opacity = (1.0 - t) * 2.0
+	}
+
+	// color.RGBA is alpha premultiplied
+	c := ft.color
+	context.FillTarget(color.RGBA{
+		R: uint8(float64(c.R) * opacity),
+		G: uint8(float64(c.G) * opacity),
+		B: uint8(float64(c.B) * opacity),
+		A: uint8(float64(c.A) * opacity),
+	})
+}
+
+// -----------------------------------------------------------------
+// Slide
+// -----------------------------------------------------------------
+
+// SlideTransition pushes the outgoing scene off the target as the
+// incoming scene slides on.
+type SlideTransition struct {
+	duration  float64
+	direction TransitionDirection
+	offset    *AffineTransform
+}
+
+// NewSlideTransition creates a slide in the given direction.
+func NewSlideTransition(duration float64, direction TransitionDirection) *SlideTransition {
+	t := new(SlideTransition)
+	t.duration = duration
+	t.direction = direction
+	t.offset = NewAffineTransform()
+	return t
+}
+
+func (st *SlideTransition) Duration() float64 {
+	return st.duration
+}
+
+func (st *SlideTransition) Render(context *RenderContext, from, to IScene, t float64) {
+	b := context.Target().Bounds()
+	dx, dy := 0.0, 0.0
+
+	switch st.direction {
+	case TransitionLeft:
+		dx = -float64(b.Dx())
+	case TransitionRight:
+		dx = float64(b.Dx())
+	case TransitionUp:
+		dy = -float64(b.Dy())
+	case TransitionDown:
+		dy = float64(b.Dy())
+	}
+
+	st.renderOffset(context, from, dx*t, dy*t)
+	st.renderOffset(context, to, dx*(t-1.0), dy*(t-1.0))
+}
+
+func (st *SlideTransition) renderOffset(context *RenderContext, scene IScene, x, y float64) {
+	if scene == nil {
+		return
+	}
+
+	context.Save()
+	st.offset.SetToTranslate(x, y)
+	context.Transform(st.offset)
+	renderScene(context, scene)
+	context.Restore()
+}
+
+// -----------------------------------------------------------------
+// Offscreen
+// -----------------------------------------------------------------
+
+// offscreen renders the incoming scene into a buffer, over the same
+// background as the target, so it can be composited onto the target.
+type offscreen struct {
+	buffer  *image.RGBA
+	context *RenderContext
+}
+
+func (o *offscreen) render(context *RenderContext, scene IScene) {
+	target := context.Target()
+	b := target.Bounds()
+
+	if o.buffer == nil || o.buffer.Bounds() != b {
+		o.buffer = image.NewRGBA(b)
+		o.context = NewRenderContext(o.buffer)
+	}
+
+	// The target only has the background drawn so far
+	draw.Draw(o.buffer, b, target, b.Min, draw.Src)
+
+	o.context.SetInterpolationAlpha(context.InterpolationAlpha())
+	renderScene(o.context, scene)
+}
+
+// -----------------------------------------------------------------
+// Cross dissolve
+// -----------------------------------------------------------------
+
+// CrossDissolveTransition blends from the outgoing to the incoming scene.
+type CrossDissolveTransition struct {
+	duration  float64
+	offscreen offscreen
+}
+
+// NewCrossDissolveTransition creates a cross dissolve.
+func NewCrossDissolveTransition(duration float64) *CrossDissolveTransition {
+	t := new(CrossDissolveTransition)
+	t.duration = duration
+	return t
+}
+
+func (ct *CrossDissolveTransition) Duration() float64 {
+	return ct.duration
+}
+
+func (ct *CrossDissolveTransition) Render(context *RenderContext, from, to IScene, t float64) {
+	ct.offscreen.render(context, to)
+	renderScene(context, from)
+
+	target := context.Target()
+	b := target.Bounds()
+	mask := image.NewUniform(color.Alpha{A: uint8(t * 255.0)})
+	draw.DrawMask(target, b, ct.offscreen.buffer, b.Min, mask, image.Point{}, draw.Over)
+}
+
+// -----------------------------------------------------------------
+// Wipe
+// -----------------------------------------------------------------
+
+// WipeTransition reveals the incoming scene behind an edge that sweeps
+// across the target.
+type WipeTransition struct {
+	duration  float64
+	direction TransitionDirection
+	offscreen offscreen
+}
+
+// NewWipeTransition creates a wipe in the given direction.
+func NewWipeTransition(duration float64, direction TransitionDirection) *WipeTransition {
+	t := new(WipeTransition)
+	t.duration = duration
+	t.direction = direction
+	return t
+}
+
+func (wt *WipeTransition) Duration() float64 {
+	return wt.duration
+}
+
+func (wt *WipeTransition) Render(context *RenderContext, from, to IScene, t float64) {
+	wt.offscreen.render(context, to)
+	renderScene(context, from)
+
+	target := context.Target()
+	r := target.Bounds()
+	w := int(float64(r.Dx()) * t)
+	h := int(float64(r.Dy()) * t)
+
+	switch wt.direction {
+	case TransitionLeft:
+		r.Min.X = r.Max.X - w
+	case TransitionRight:
+		r.Max.X = r.Min.X + w
+	case TransitionUp:
+		r.Min.Y = r.Max.Y - h
+	case TransitionDown:
+		r.Max.Y = r.Min.Y + h
+	}
+
+	draw.Draw(target, r, wt.offscreen.buffer, r.Min, draw.Src)
+}
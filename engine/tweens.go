@@ -1,11 +1,21 @@
 package engine
 
-import "math"
+import (
+	"image/color"
+	"math"
+)
+
+// EasingFunction maps time to a value in Robert Penner's form: t is the
+// time position from 0 to d, b the start value and c the change in value.
+type EasingFunction func(t, b, c, d float64) float64
 
 // LinearEasing is a basic linear lerp
 // t,b,c,d
 // timePosition ranges from 0 to duration
 func LinearEasing(timePosition, startValue, deltaValue, duration float64) float64 {
+	if duration <= 0.0 {
+		return startValue + deltaValue
+	}
 	return deltaValue*timePosition/duration + startValue
 }
 
@@ -14,3 +24,244 @@ func Lerp(x1, y1, y float64) float64 {
 	x := x1 / y1 * y
 	return math.Round(x)
 }
+
+// ITween is a Tween or Timeline. Tweens can be advanced with Update,
+// positioned with Seek or run on a node with an ActionManager, where they
+// follow the node's pause and time scale.
+type ITween interface {
+	IAction
+
+	// Update advances the tween by dt seconds.
+	Update(dt float64)
+	// Seek positions the tween time seconds from its start, including
+	// any delay. Callbacks fire when time moves forward past them.
+	Seek(time float64)
+	// Restart rewinds the tween without applying its start value.
+	Restart()
+
+	Time() float64
+	IsComplete() bool
+}
+
+// Tween animates a value from one value to another over a duration. The
+// value can be a float, Vector3, color.RGBA or, with NewTweenFunc,
+// anything that can be set from a float.
+type Tween struct {
+	duration float64
+	delay    float64
+	// Extra plays, or RepeatForever
+	repeats int
+	// Alternate plays run backwards
+	yoyo   bool
+	easing EasingFunction
+
+	// apply sets the value for an eased progress of 0 to 1
+	apply func(p float64)
+
+	onStart    func()
+	onRepeat   func(play int)
+	onComplete func()
+
+	time      float64
+	play      int
+	started   bool
+	completed bool
+}
+
+// NewTweenFunc tweens from from to to calling set with each value, for
+// example, to tween a node's opacity with SetOpacity.
+func NewTweenFunc(from, to, duration float64, set func(value float64)) *Tween {
+	return newTween(duration, func(p float64) {
+		set(lerp(from, to, p))
+	})
+}
+
+// NewFloatTween tweens target from from to to.
+func NewFloatTween(target *float64, from, to, duration float64) *Tween {
+	return newTween(duration, func(p float64) {
+		*target = lerp(from, to, p)
+	})
+}
+
+// NewVectorTween tweens target from from to to. from and to are copied.
+func NewVectorTween(target, from, to *Vector3, duration float64) *Tween {
+	f, t := *from, *to
+	return newTween(duration, func(p float64) {
+		target.Set3Components(lerp(f.X, t.X, p), lerp(f.Y, t.Y, p), lerp(f.Z, t.Z, p))
+	})
+}
+
+// NewColorTween tweens target from from to to.
+func NewColorTween(target *color.RGBA, from, to color.RGBA, duration float64) *Tween {
+	return newTween(duration, func(p float64) {
+		*target = lerpColor(from, to, p)
+	})
+}
+
+func newTween(duration float64, apply func(p float64)) *Tween {
+	t := new(Tween)
+	t.duration = duration
+	t.easing = LinearEasing
+	t.apply = apply
+	return t
+}
+
+// SetDuration sets the length of one play.
+func (t *Tween) SetDuration(duration float64) {
+	t.duration = duration
+}
+
+// SetDelay sets how long the tween waits before starting.
+func (t *Tween) SetDelay(delay float64) {
+	t.delay = delay
+}
+
+// SetEasing sets the easing, LinearEasing by default.
+func (t *Tween) SetEasing(easing EasingFunction) {
+	t.easing = easing
+}
+
+// SetRepeat sets how many more times the tween plays after the first, or
+// RepeatForever.
+func (t *Tween) SetRepeat(repeats int) {
+	t.repeats = repeats
+}
+
+// SetYoyo makes every other play run backwards, from to to then back.
+func (t *Tween) SetYoyo(yoyo bool) {
+	t.yoyo = yoyo
+}
+
+// OnStart sets a callback for when the delay has passed.
+func (t *Tween) OnStart(callback func()) {
+	t.onStart = callback
+}
+
+// OnRepeat sets a callback for the start of each play after the first,
+// with the play's index.
+func (t *Tween) OnRepeat(callback func(play int)) {
+	t.onRepeat = callback
+}
+
+// OnComplete sets a callback for when the last play finishes.
+func (t *Tween) OnComplete(callback func()) {
+	t.onComplete = callback
+}
+
+// Duration returns the delay plus every play, or +Inf if repeating
+// forever.
+func (t *Tween) Duration() float64 {
+	if t.repeats == RepeatForever && t.duration > 0.0 {
+		return math.Inf(1)
+	}
+	return t.delay + t.duration*float64(t.plays())
+}
+
+func (t *Tween) plays() int {
+	if t.repeats == RepeatForever {
+		return 1
+	}
+	return t.repeats + 1
+}
+
+// Time returns the time since the start, including the delay.
+func (t *Tween) Time() float64 {
+	return t.time
+}
+
+// IsComplete is true once the last play has finished.
+func (t *Tween) IsComplete() bool {
+	return t.completed
+}
+
+// Restart rewinds the tween so it can play again.
+func (t *Tween) Restart() {
+	t.time = 0.0
+	t.play = 0
+	t.started = false
+	t.completed = false
+}
+
+// Update advances the tween by dt seconds.
+func (t *Tween) Update(dt float64) {
+	if t.completed {
+		return
+	}
+	t.Seek(t.time + dt)
+}
+
+// Seek positions the tween time seconds from its start.
+func (t *Tween) Seek(time float64) {
+	total := t.Duration()
+	time = math.Max(0.0, math.Min(time, total))
+	forward := time >= t.time
+	t.time = time
+
+	local := time - t.delay
+	if local < 0.0 || (local == 0.0 && !forward) {
+		// Back before the start
+		if t.started {
+			t.apply(0.0)
+		}
+		t.play = 0
+		t.started = false
+		t.completed = false
+		return
+	}
+
+	if !t.started {
+		t.started = true
+		if forward && t.onStart != nil {
+			t.onStart()
+		}
+	}
+
+	// Which play time is in and how far through it
+	play := 0
+	within := t.duration
+	if t.duration > 0.0 {
+		play = int(local / t.duration)
+		if t.repeats != RepeatForever && play >= t.plays() {
+			play = t.plays() - 1
+		}
+		within = local - float64(play)*t.duration
+	}
+
+	if forward {
+		for t.play < play {
+			t.play++
+			if t.onRepeat != nil {
+				t.onRepeat(t.play)
+			}
+		}
+	} else {
+		t.play = play
+	}
+
+	p := 1.0
+	if t.duration > 0.0 {
+		p = within / t.duration
+	}
+	if t.yoyo && play%2 == 1 {
+		p = 1.0 - p
+	}
+	t.apply(t.easing(p, 0.0, 1.0, 1.0))
+
+	if time < total {
+		t.completed = false
+	} else if !t.completed {
+		t.completed = true
+		if forward && t.onComplete != nil {
+			t.onComplete()
+		}
+	}
+}
+
+// start and update run the tween as an action, the node is ignored.
+func (t *Tween) start(target INode) {
+	t.Restart()
+}
+
+func (t *Tween) update(elapsed float64) {
+	t.Seek(elapsed)
+}
@@ -0,0 +1,139 @@
+package engine
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WalkOrder is the order Walk visits nodes in.
+type WalkOrder int
+
+const (
+	// WalkPreOrder visits a group before its children.
+	WalkPreOrder WalkOrder = iota
+	// WalkPostOrder visits a group after its children.
+	WalkPostOrder
+	// WalkBreadthFirst visits every node at one depth before the next.
+	WalkBreadthFirst
+)
+
+// WalkAction is returned by a Visitor to control the walk.
+type WalkAction int
+
+const (
+	// WalkContinue carries on walking.
+	WalkContinue WalkAction = iota
+	// WalkPrune skips the node's children. In post-order the children
+	// have already been visited so it is the same as WalkContinue.
+	WalkPrune
+	// WalkStop ends the walk.
+	WalkStop
+)
+
+// Visitor is called for each node with its depth below the root, which
+// is at depth 0.
+type Visitor func(n INode, depth int) WalkAction
+
+// Walk visits root and its subtree in the given order, children in render
+// order. It returns false if a visitor stopped the walk. The visitor
+// mustn't add or remove nodes of the groups being walked.
+func Walk(root INode, order WalkOrder, visit Visitor) bool {
+	switch order {
+	case WalkPostOrder:
+		return walkPostOrder(root, 0, visit)
+	case WalkBreadthFirst:
+		return walkBreadthFirst(root, visit)
+	}
+	return walkPreOrder(root, 0, visit)
+}
+
+func walkPreOrder(n INode, depth int, visit Visitor) bool {
+	switch visit(n, depth) {
+	case WalkStop:
+		return false
+	case WalkPrune:
+		return true
+	}
+
+	if g, ok := n.(IGroupNode); ok {
+		for _, child := range g.Children() {
+			if !walkPreOrder(child, depth+1, visit) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func walkPostOrder(n INode, depth int, visit Visitor) bool {
+	if g, ok := n.(IGroupNode); ok {
+		for _, child := range g.Children() {
+			if !walkPostOrder(child, depth+1, visit) {
+				return false
+			}
+		}
+	}
+
+	return visit(n, depth) != WalkStop
+}
+
+func walkBreadthFirst(root INode, visit Visitor) bool {
+	type queued struct {
+		node  INode
+		depth int
+	}
+
+	queue := []queued{{root, 0}}
+
+	for len(queue) > 0 {
+		q := queue[0]
+		queue[0] = queued{}
+		queue = queue[1:]
+
+		switch visit(q.node, q.depth) {
+		case WalkStop:
+			return false
+		case WalkPrune:
+			continue
+		}
+
+		if g, ok := q.node.(IGroupNode); ok {
+			for _, child := range g.Children() {
+				queue = append(queue, queued{child, q.depth + 1})
+			}
+		}
+	}
+
+	return true
+}
+
+// DumpTree writes an indented outline of root's subtree, one node per
+// line, for debugging.
+func DumpTree(w io.Writer, root INode) error {
+	var err error
+
+	Walk(root, WalkPreOrder, func(n INode, depth int) WalkAction {
+		typeName, terr := NodeTypeName(n)
+		if terr != nil {
+			typeName = fmt.Sprintf("%T", n)
+		}
+
+		line := fmt.Sprintf("%s%s '%s' %v", strings.Repeat("  ", depth), typeName, n.Name(), n.Position())
+		if !n.IsVisible() {
+			line += " (invisible)"
+		}
+		if tags := n.Tags(); len(tags) > 0 {
+			line += " @" + strings.Join(tags, " @")
+		}
+
+		_, err = fmt.Fprintln(w, line)
+		if err != nil {
+			return WalkStop
+		}
+		return WalkContinue
+	})
+
+	return err
+}
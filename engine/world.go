@@ -0,0 +1,99 @@
+package engine
+
+// Every computed world transform is stamped with a version that is unique
+// across all nodes. A node's cached world transform is valid as long as
+// its local transform hasn't changed and its parent's world transform
+// still carries the version the cache was computed from. This means a
+// change to any ancestor invalidates the whole subtree without having to
+// visit it; descendants revalidate lazily when next asked.
+var worldVersions uint64
+
+func nextWorldVersion() uint64 {
+	worldVersions++
+	return worldVersions
+}
+
+type worldCache struct {
+	transform *AffineTransform
+	// Zero means never computed
+	version uint64
+
+	// What the cache was computed from
+	localVersion  uint64
+	parentVersion uint64
+
+	inverse        *AffineTransform
+	inverseVersion uint64
+}
+
+func newWorldCache() *worldCache {
+	c := new(worldCache)
+	c.transform = NewAffineTransform()
+	c.inverse = NewAffineTransform()
+	return c
+}
+
+// worldTransform returns the node-to-world transform and its version,
+// recomputing it if the node or any ancestor has changed.
+func (n *BaseNode) worldTransform() (*AffineTransform, uint64) {
+	var parentWorld *AffineTransform
+	var parentVersion uint64
+
+	if n.parent != nil {
+		parentWorld, parentVersion = n.parent.worldTransform()
+	}
+
+	c := n.world
+	if c.version == 0 || c.localVersion != n.localVersion || c.parentVersion != parentVersion {
+		if parentWorld != nil {
+			// Same concatenation order the RenderContext uses
+			AffineTransformMultiply(n.calcTransform(), parentWorld, c.transform)
+		} else {
+			c.transform.SetWithAT(n.calcTransform())
+		}
+
+		c.localVersion = n.localVersion
+		c.parentVersion = parentVersion
+		c.version = nextWorldVersion()
+	}
+
+	return c.transform, c.version
+}
+
+// NodeToWorldTransform returns the cached transform from this node's local
+// space to world space.
+func (n *BaseNode) NodeToWorldTransform() *AffineTransform {
+	at, _ := n.worldTransform()
+	return at
+}
+
+// WorldToNodeTransform returns the cached transform from world space to
+// this node's local space.
+func (n *BaseNode) WorldToNodeTransform() *AffineTransform {
+	at, version := n.worldTransform()
+
+	c := n.world
+	if c.inverseVersion != version {
+		AffineTransformInvertTo(at, c.inverse)
+		c.inverseVersion = version
+	}
+
+	return c.inverse
+}
+
+// NodeToWorld maps a point in this node's local space to world space.
+func (n *BaseNode) NodeToWorld(local *Vector3, out *Vector3) {
+	n.NodeToWorldTransform().ApplyTo(local, out)
+}
+
+// WorldToNode maps a world point into this node's local space.
+func (n *BaseNode) WorldToNode(world *Vector3, out *Vector3) {
+	n.WorldToNodeTransform().ApplyTo(world, out)
+}
+
+// NodeToNode maps a point in this node's local space into target's local
+// space.
+func (n *BaseNode) NodeToNode(local *Vector3, target INode, out *Vector3) {
+	n.NodeToWorld(local, out)
+	target.WorldToNode(out, out)
+}